@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"path/filepath"
+	"strings"
+)
+
+// generate writes a router or resolver from router.go.tmpl or resolver.go.tmpl
+// into a file named after it, or the Twirp bindings of a .proto file.
+func generate(args []string) error {
+	if len(args) < 1 {
+		return errors.New("usage: winter generate router|resolver|rpc <name>")
+	}
+
+	kind := args[0]
+	if kind == "rpc" {
+		return generateRPC(args[1:])
+	}
+
+	defaultDir := map[string]string{
+		"router": "routers",
+		"resolver": "resolvers",
+	}[kind]
+	if defaultDir == "" {
+		return errors.New("can not generate " + kind + ", use router, resolver or rpc")
+	}
+
+	flags := flag.NewFlagSet("generate " + kind, flag.ExitOnError)
+	dir := flags.String("dir", defaultDir, "directory of the package to write into")
+	force := flags.Bool("force", false, "overwrite an existing file")
+	flags.Parse(args[1:])
+	if flags.NArg() != 1 {
+		return errors.New("usage: winter generate " + kind + " [-dir " + defaultDir + "] [-force] <name>")
+	}
+
+	name := flags.Arg(0)
+	if exportedName(name) == "" {
+		return errors.New(name + " is not a valid name")
+	}
+
+	absDir, err := filepath.Abs(*dir)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]string{
+		"Name": exportedName(name),
+		"Path": strings.ReplaceAll(fileName(name), "_", "-"),
+		"Package": filepath.Base(absDir),
+	}
+	return writeTemplate(kind + ".go.tmpl", filepath.Join(*dir, fileName(name) + ".go"), data, *force)
+}
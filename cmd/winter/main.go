@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"github.com/steplems/winter/core"
+	"os"
+	"sort"
+)
+
+type command struct {
+	usage string
+	run func(args []string) error
+}
+
+var (
+	logger = core.NewLogger("winter")
+
+	commands = map[string]command{
+		"new": {"new <app>  create an app with a server, an example router, config and tests", newProject},
+		"generate": {"generate router|resolver [-dir dir] [-force] <name> | rpc [-out dir] <file.proto>  write a router, resolver or Twirp bindings", generate},
+		"run": {"run [-addr :5539] [-dir .] [-interval 500ms] [-- app args]  build, start and restart the app on changes", run},
+		"routes": {"routes [-addr http://localhost:5539]  list the routes of a server running in Debug mode", routes},
+	}
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := command.run(os.Args[2:]); err != nil {
+		logger.Err(err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	names := []string{}
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "Usage: winter <command>")
+	for _, name := range names {
+		fmt.Fprintln(os.Stderr, "  winter " + commands[name].usage)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// newProject creates a project from the templates in new/. The argument is the
+// module path of the app, its last element names the directory.
+func newProject(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ExitOnError)
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return errors.New("usage: winter new <app>")
+	}
+
+	module := flags.Arg(0)
+	app := path.Base(module)
+	if _, err := os.Stat(app); err == nil {
+		return errors.New(app + " already exists")
+	}
+
+	data := map[string]string{
+		"App": app,
+		"Module": module,
+	}
+	for _, name := range templateNames("new") {
+		file := filepath.Join(app, filepath.FromSlash(strings.TrimSuffix(strings.TrimPrefix(name, "new/"), ".tmpl")))
+		if err := writeTemplate(name, file, data, false); err != nil {
+			return err
+		}
+	}
+
+	tidy := exec.Command("go", "mod", "tidy")
+	tidy.Dir = app
+	tidy.Stdout, tidy.Stderr = os.Stdout, os.Stderr
+	if err := tidy.Run(); err != nil {
+		logger.Warn("Could not run go mod tidy in", app + ":", err)
+	}
+
+	logger.Info("Your app is ready, run it with: cd " + app + " && winter run")
+	return nil
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+type (
+	protoFile struct {
+		Package string
+		GoPackage string
+		Services []protoService
+	}
+
+	protoService struct {
+		Name string
+		FullName string
+		Methods []protoMethod
+	}
+
+	protoMethod struct {
+		Name string
+		Input string
+		Output string
+	}
+
+	protoParser struct {
+		tokens []string
+		pos int
+	}
+)
+
+// parseProto reads the package, go_package option and services of a .proto file.
+// Messages are not parsed, their Go types come from protoc-gen-go.
+func parseProto(source string) (*protoFile, error) {
+	tokens, err := tokenizeProto(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &protoParser{tokens: tokens}
+	file := &protoFile{}
+
+	for p.pos < len(p.tokens) {
+		switch token := p.next(); token {
+		case "package":
+			file.Package = p.next()
+			p.expect(";")
+		case "option":
+			name := p.next()
+			p.expect("=")
+			value := p.next()
+			if name == "go_package" {
+				file.GoPackage = strings.Trim(value, `"`)
+			}
+		case "service":
+			service, err := p.service(file.Package)
+			if err != nil {
+				return nil, err
+			}
+			file.Services = append(file.Services, service)
+		case "{":
+			p.skipBlock()
+		}
+	}
+
+	if len(file.Services) == 0 {
+		return nil, errors.New("no service found")
+	}
+	return file, nil
+}
+
+// goPackageName is the name from go_package, "path;name" or the last element of
+// the path, and the last element of the proto package otherwise.
+func (f *protoFile) goPackageName() string {
+	if i := strings.LastIndex(f.GoPackage, ";"); i >= 0 {
+		return f.GoPackage[i + 1:]
+	}
+	if f.GoPackage != "" {
+		return strings.ReplaceAll(path.Base(f.GoPackage), "-", "_")
+	}
+	if f.Package != "" {
+		return f.Package[strings.LastIndex(f.Package, ".") + 1:]
+	}
+	return "main"
+}
+
+func (p *protoParser) service(protoPackage string) (protoService, error) {
+	service := protoService{Name: p.next()}
+	service.FullName = service.Name
+	if protoPackage != "" {
+		service.FullName = protoPackage + "." + service.Name
+	}
+	if !p.expect("{") {
+		return service, fmt.Errorf("service %s: expected {", service.Name)
+	}
+
+	for p.pos < len(p.tokens) {
+		switch token := p.next(); token {
+		case "}":
+			return service, nil
+		case "rpc":
+			method := protoMethod{Name: p.next()}
+
+			input, err := p.messageType(protoPackage)
+			if err != nil {
+				return service, fmt.Errorf("rpc %s.%s: %w", service.Name, method.Name, err)
+			}
+			if !p.expect("returns") {
+				return service, fmt.Errorf("rpc %s.%s: expected returns", service.Name, method.Name)
+			}
+			output, err := p.messageType(protoPackage)
+			if err != nil {
+				return service, fmt.Errorf("rpc %s.%s: %w", service.Name, method.Name, err)
+			}
+
+			method.Input, method.Output = input, output
+			service.Methods = append(service.Methods, method)
+
+			if p.peek() == "{" {
+				p.next()
+				p.skipBlock()
+			} else {
+				p.expect(";")
+			}
+		case "{":
+			p.skipBlock()
+		}
+	}
+	return service, fmt.Errorf("service %s: missing }", service.Name)
+}
+
+// messageType reads "(Type)", types of the file's own package lose their package prefix.
+func (p *protoParser) messageType(protoPackage string) (string, error) {
+	if !p.expect("(") {
+		return "", errors.New("expected (")
+	}
+
+	name := p.next()
+	if name == "stream" {
+		return "", errors.New("streaming is not supported by Twirp")
+	}
+	if !p.expect(")") {
+		return "", errors.New("expected )")
+	}
+
+	name = strings.TrimPrefix(name, ".")
+	if protoPackage != "" {
+		name = strings.TrimPrefix(name, protoPackage + ".")
+	}
+	if strings.Contains(name, ".") {
+		return "", fmt.Errorf("type %s is not declared in this file's package", name)
+	}
+	return name, nil
+}
+
+func (p *protoParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	p.pos++
+	return p.tokens[p.pos - 1]
+}
+
+func (p *protoParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *protoParser) expect(token string) bool {
+	if p.peek() != token {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+// skipBlock skips to the } closing a { that was just read.
+func (p *protoParser) skipBlock() {
+	for depth := 1; depth > 0 && p.pos < len(p.tokens); {
+		switch p.next() {
+		case "{":
+			depth++
+		case "}":
+			depth--
+		}
+	}
+}
+
+// tokenizeProto splits source into identifiers, strings and symbols, dropping comments.
+func tokenizeProto(source string) ([]string, error) {
+	tokens := []string{}
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i + 1 < len(runes) && runes[i + 1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i + 1 < len(runes) && runes[i + 1] == '*':
+			i += 2
+			for i + 1 < len(runes) && !(runes[i] == '*' && runes[i + 1] == '/') {
+				i++
+			}
+			i += 2
+		case r == '"' || r == '\'':
+			start := i
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(runes) {
+				line := strings.Count(string(runes[:start]), "\n") + 1
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			i++
+			tokens = append(tokens, `"` + string(runes[start + 1:i - 1]) + `"`)
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		default:
+			tokens = append(tokens, string(r))
+			i++
+		}
+	}
+	return tokens, nil
+}
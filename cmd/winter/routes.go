@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/steplems/winter/core"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// routes fetches the listing served on /_winter/routes by servers in Debug mode.
+func routes(args []string) error {
+	flags := flag.NewFlagSet("routes", flag.ExitOnError)
+	addr := flags.String("addr", "http://localhost:5539", "address of the running server")
+	flags.Parse(args)
+
+	res, err := http.Get(strings.TrimSuffix(*addr, "/") + "/_winter/routes")
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %s, is it running in Debug mode?", *addr, res.Status)
+	}
+
+	body := struct {
+		Message []core.RouteInfo `json:"message"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return fmt.Errorf("could not read routes: %w", err)
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "METHODS\tPATH\tNAME\tHANDLER\tMIDDLEWARE")
+	for _, route := range body.Message {
+		methods := strings.Join(route.Methods, ",")
+		if methods == "" {
+			methods = "*"
+		}
+		path := route.Path
+		if route.Host != "" {
+			path = route.Host + path
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", methods, path, route.Name, route.Handler, strings.Join(route.Middleware, " > "))
+	}
+	return table.Flush()
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// generateRPC writes the Twirp service interfaces, routers and clients of a .proto
+// file from rpc.go.tmpl. The messages are the types protoc-gen-go generates into the
+// same package.
+func generateRPC(args []string) error {
+	flags := flag.NewFlagSet("generate rpc", flag.ExitOnError)
+	out := flags.String("out", "", "directory to write into, the directory of the .proto file by default")
+	force := flags.Bool("force", false, "overwrite an existing file")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return errors.New("usage: winter generate rpc [-out dir] [-force] <file.proto>")
+	}
+
+	protoPath := flags.Arg(0)
+	source, err := os.ReadFile(protoPath)
+	if err != nil {
+		return err
+	}
+
+	file, err := parseProto(string(source))
+	if err != nil {
+		return errors.New(protoPath + ": " + err.Error())
+	}
+
+	if *out == "" {
+		*out = filepath.Dir(protoPath)
+	}
+	base := strings.TrimSuffix(filepath.Base(protoPath), filepath.Ext(protoPath))
+
+	data := map[string]interface{}{
+		"Source": filepath.Base(protoPath),
+		"Package": file.goPackageName(),
+		"Services": file.Services,
+	}
+	return writeTemplate("rpc.go.tmpl", filepath.Join(*out, base + ".winter.go"), data, *force)
+}
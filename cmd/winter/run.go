@@ -0,0 +1,221 @@
+package main
+
+import (
+	"flag"
+	"github.com/steplems/winter/core"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+type process struct {
+	cmd *exec.Cmd
+	binary string
+	stopping atomic.Bool
+	done chan struct{}
+}
+
+// run builds the app, starts it and restarts it when a .go file changes. The
+// listening socket is opened here and handed to every build, so the old process
+// finishes its requests through GracefulShutdown while the new one accepts.
+// Windows can neither hand a socket to a child process nor interrupt it, there the
+// app listens on its own address and is stopped before the next build starts.
+func run(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	addr := flags.String("addr", ":5539", "address to listen on, ignored on Windows")
+	dir := flags.String("dir", ".", "directory of the app's main package")
+	interval := flags.Duration("interval", 500 * time.Millisecond, "how often files are checked for changes")
+	flags.Parse(args)
+
+	handOff := runtime.GOOS != "windows"
+
+	var socket *os.File
+	if handOff {
+		listener, err := net.Listen("tcp", *addr)
+		if err != nil {
+			return err
+		}
+		if socket, err = listener.(*net.TCPListener).File(); err != nil {
+			return err
+		}
+	}
+
+	binDir, err := os.MkdirTemp("", "winter-run")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(binDir)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	var app *process
+	builds := 0
+	restart := func() {
+		builds++
+		binary := filepath.Join(binDir, "app-" + strconv.Itoa(builds))
+		if runtime.GOOS == "windows" {
+			binary += ".exe"
+		}
+
+		if !build(*dir, binary) {
+			return
+		}
+
+		if !handOff {
+			app.stop()
+			app.wait(10 * time.Second)
+			app = nil
+		}
+
+		next, err := startProcess(binary, *dir, socket, flags.Args())
+		if err != nil {
+			logger.Err("Could not start the app:", err)
+			return
+		}
+
+		app.stop()
+		app = next
+	}
+
+	if handOff {
+		logger.Info("Listening on " + *addr + ", watching " + *dir)
+	} else {
+		logger.Info("Watching " + *dir + ", the app listens on its own address")
+	}
+	restart()
+
+	files := scanFiles(*dir)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			app.stop()
+			app.wait(10 * time.Second)
+			return nil
+		case <-ticker.C:
+			current := scanFiles(*dir)
+			if filesChanged(files, current) {
+				files = current
+				logger.Info("Change detected, rebuilding")
+				restart()
+			}
+		}
+	}
+}
+
+// build reports compiler errors line by line and keeps the watcher running.
+func build(dir, binary string) bool {
+	cmd := exec.Command("go", "build", "-o", binary, ".")
+	cmd.Dir = dir
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return true
+	}
+
+	logger.Err("Build failed:", err)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			logger.Err(line)
+		}
+	}
+	return false
+}
+
+func startProcess(binary, dir string, socket *os.File, args []string) (*process, error) {
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = dir
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if socket != nil {
+		// ExtraFiles start at descriptor 3, after stdin, stdout and stderr.
+		cmd.ExtraFiles = []*os.File{socket}
+		cmd.Env = append(os.Environ(), core.ListenFDEnv + "=3")
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &process{cmd: cmd, binary: binary, done: make(chan struct{})}
+	go func() {
+		if err := cmd.Wait(); err != nil && !p.stopping.Load() {
+			logger.Warn("The app exited:", err)
+		}
+		os.Remove(binary)
+		close(p.done)
+	}()
+	return p, nil
+}
+
+// stop interrupts the process so it goes through its graceful shutdown. Where the
+// process cannot be interrupted, like on Windows, it is killed.
+func (p *process) stop() {
+	if p == nil {
+		return
+	}
+
+	p.stopping.Store(true)
+	if err := p.cmd.Process.Signal(os.Interrupt); err != nil {
+		p.cmd.Process.Kill()
+	}
+}
+
+func (p *process) wait(timeout time.Duration) {
+	if p == nil {
+		return
+	}
+
+	select {
+	case <-p.done:
+	case <-time.After(timeout):
+		p.cmd.Process.Kill()
+	}
+}
+
+// scanFiles records the modification times of the Go sources and module files
+// below dir, skipping hidden and vendor directories.
+func scanFiles(dir string) map[string]time.Time {
+	files := map[string]time.Time{}
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		name := info.Name()
+		if info.IsDir() {
+			if path != dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if strings.HasSuffix(name, ".go") || name == "go.mod" || name == "go.sum" {
+			files[path] = info.ModTime()
+		}
+		return nil
+	})
+	return files
+}
+
+func filesChanged(before, after map[string]time.Time) bool {
+	if len(before) != len(after) {
+		return true
+	}
+	for path, modTime := range after {
+		if !before[path].Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
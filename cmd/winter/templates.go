@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+// templateSources are searched in order: the project's .winter/templates, the
+// directory in WINTER_TEMPLATES for templates shared by a team, the built-in ones.
+func templateSources() []fs.FS {
+	sources := []fs.FS{os.DirFS(filepath.Join(".winter", "templates"))}
+	if dir := os.Getenv("WINTER_TEMPLATES"); dir != "" {
+		sources = append(sources, os.DirFS(dir))
+	}
+
+	builtin, _ := fs.Sub(builtinTemplates, "templates")
+	return append(sources, builtin)
+}
+
+// templateNames lists the templates below dir in any of the sources, so overrides
+// can add files to a project as well as replace them.
+func templateNames(dir string) []string {
+	seen := map[string]bool{}
+	for _, source := range templateSources() {
+		fs.WalkDir(source, dir, func(name string, entry fs.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() && strings.HasSuffix(name, ".tmpl") {
+				seen[name] = true
+			}
+			return nil
+		})
+	}
+
+	names := []string{}
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func renderTemplate(name string, data interface{}) ([]byte, error) {
+	for _, source := range templateSources() {
+		text, err := fs.ReadFile(source, name)
+		if err != nil {
+			continue
+		}
+
+		fileTemplate, err := template.New(name).Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse template %s: %w", name, err)
+		}
+
+		out := &bytes.Buffer{}
+		if err := fileTemplate.Execute(out, data); err != nil {
+			return nil, fmt.Errorf("could not render template %s: %w", name, err)
+		}
+
+		if path.Ext(strings.TrimSuffix(name, ".tmpl")) == ".go" {
+			formatted, err := format.Source(out.Bytes())
+			if err != nil {
+				return nil, fmt.Errorf("template %s does not render valid Go: %w", name, err)
+			}
+			return formatted, nil
+		}
+		return out.Bytes(), nil
+	}
+	return nil, fmt.Errorf("missing template %s", name)
+}
+
+// writeTemplate renders name into file, refusing to overwrite existing files unless forced.
+func writeTemplate(name, file string, data interface{}, force bool) error {
+	if _, err := os.Stat(file); err == nil && !force {
+		return fmt.Errorf("%s already exists", file)
+	}
+
+	out, err := renderTemplate(name, data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, out, 0644); err != nil {
+		return err
+	}
+
+	logger.Info("Created", file)
+	return nil
+}
+
+// exportedName turns names like "user-profile" or "user_profile" into "UserProfile".
+func exportedName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	exported := &strings.Builder{}
+	for _, word := range words {
+		runes := []rune(word)
+		exported.WriteString(strings.ToUpper(string(runes[0])) + string(runes[1:]))
+	}
+	return exported.String()
+}
+
+// fileName turns names like "UserProfile" or "user-profile" into "user_profile".
+func fileName(name string) string {
+	file := &strings.Builder{}
+	for i, r := range exportedName(name) {
+		if unicode.IsUpper(r) && i > 0 {
+			file.WriteRune('_')
+		}
+		file.WriteRune(unicode.ToLower(r))
+	}
+	return file.String()
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// RequestID takes the request id from the X-Request-ID header or generates one,
+// echoes it back in the response and makes it available through Context.RequestID.
+func RequestID() MiddlewareResolver {
+	return func(ctx *MiddlewareContext) {
+		id := ctx.Request.Header.Get(header_request_id)
+		if id == "" {
+			id = RandomID()
+		}
+
+		ctx.Header(header_request_id, id)
+		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), request_id_key, id))
+		ctx.Next()
+	}
+}
+
+// AccessLog writes a line for every request once it is resolved. The format is
+// AccessLogCombined, AccessLogJSON or a text/template executed with an AccessLogEntry.
+// Lines go to os.Stdout when output is nil.
+func AccessLog(format string, output io.Writer) MiddlewareResolver {
+	if output == nil {
+		output = os.Stdout
+	}
+
+	var lineTemplate *template.Template
+	if format != AccessLogJSON {
+		if format == AccessLogCombined {
+			format = access_log_combined_template
+		}
+		lineTemplate = template.Must(template.New("access_log").Parse(format))
+	}
+
+	mutex := &sync.Mutex{}
+
+	return func(ctx *MiddlewareContext) {
+		start := time.Now()
+		ctx.Next()
+
+		// net/http answers 200 for a handler that wrote nothing.
+		status := ctx.StatusCode()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		entry := AccessLogEntry{
+			Time: start,
+			RequestID: ctx.RequestID(),
+			RemoteIP: remoteIP(ctx.Request),
+			Method: ctx.Request.Method,
+			URI: ctx.Request.RequestURI,
+			Proto: ctx.Request.Proto,
+			Status: status,
+			Bytes: ctx.BytesWritten(),
+			Referer: ctx.Request.Referer(),
+			UserAgent: ctx.Request.UserAgent(),
+			Duration: ctx.TrackTime(),
+		}
+
+		line := &strings.Builder{}
+		if lineTemplate == nil {
+			json.NewEncoder(line).Encode(entry)
+		} else {
+			if err := lineTemplate.Execute(line, entry); err != nil {
+				RequestLogger.Err("Could not format access log:", err)
+				return
+			}
+			line.WriteString("\n")
+		}
+
+		mutex.Lock()
+		io.WriteString(output, line.String())
+		mutex.Unlock()
+	}
+}
+
+func (c *Context) RequestID() string {
+	id, _ := c.Request.Context().Value(request_id_key).(string)
+	return id
+}
+
+// Logger returns RequestLogger tagged with the request id, when there is one.
+func (c *Context) Logger() *Logger {
+	if id := c.RequestID(); id != "" {
+		return RequestLogger.With("request_id", id)
+	}
+	return RequestLogger
+}
+
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
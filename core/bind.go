@@ -0,0 +1,201 @@
+package core
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Bind wraps a resolver that takes its request as a struct. The struct is filled
+// from the body and from fields tagged with `path`, `query` or `header`, then
+// checked against its `validate` tags. Binding failures answer with the router's
+// 400 error and validation failures with its 422 error, translated like ErrorResponse,
+// with every failing field appended.
+func Bind[T any](resolver func(ctx *Context, req *T) Response) Resolver {
+	return func(ctx *Context) Response {
+		req := new(T)
+		if err := ctx.Bind(req); err != nil {
+			var mediaErr *Error
+			if errors.As(err, &mediaErr) {
+				return NewErrorResponse(mediaErr)
+			}
+			return NewErrorResponse(ctx.router.Errors.Get(http.StatusBadRequest).WithDetail(err.Error()))
+		}
+		if err := ctx.Validate(req); err != nil {
+			unprocessable := ctx.localize(ctx.router.Errors.Get(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+			return NewErrorResponse(unprocessable.WithDetail(err.Error()))
+		}
+		return resolver(ctx, req)
+	}
+}
+
+func (c *Context) Bind(v interface{}) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Struct {
+		return errors.New("bind target must be a pointer to a struct")
+	}
+
+	if c.hasBody() {
+		if err := c.GetBody(v); err != nil {
+			return &ParamError{Source: "body", Err: err}
+		}
+	}
+
+	return bindFields(target.Elem(), c.GetParams(), c.Request.URL.Query(), c.Request.Header)
+}
+
+func (c *Context) hasBody() bool {
+	return c.Request.Body != nil && c.Request.Body != http.NoBody && c.Request.ContentLength != 0
+}
+
+func (p *ParamError) Error() string {
+	if p.Field == "" {
+		return "invalid " + p.Source + ": " + p.Err.Error()
+	}
+	return fmt.Sprintf("invalid %s parameter %q (%q): %v", p.Source, p.Field, p.Value, p.Err)
+}
+
+func (p *ParamError) Unwrap() error {
+	return p.Err
+}
+
+func bindFields(target reflect.Value, params map[string]string, query url.Values, header http.Header) error {
+	targetType := target.Type()
+
+	for i := 0; i < target.NumField(); i++ {
+		field := targetType.Field(i)
+		fieldValue := target.Field(i)
+
+		if field.Anonymous && fieldValue.Kind() == reflect.Struct {
+			if err := bindFields(fieldValue, params, query, header); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		var source, name string
+		var values []string
+
+		if name = field.Tag.Get(bind_tag_path); name != "" {
+			source = bind_tag_path
+			if param, ok := params[name]; ok {
+				values = []string{param}
+			}
+		} else if name = field.Tag.Get(bind_tag_query); name != "" {
+			source, values = bind_tag_query, query[name]
+		} else if name = field.Tag.Get(bind_tag_header); name != "" {
+			source, values = bind_tag_header, header.Values(name)
+		} else {
+			continue
+		}
+
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := setField(fieldValue, values); err != nil {
+			return &ParamError{
+				Source: source,
+				Field: name,
+				Value: values[0],
+				Err: err,
+			}
+		}
+	}
+
+	return nil
+}
+
+func setField(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Ptr {
+		value := reflect.New(field.Type().Elem())
+		if err := setField(value.Elem(), values); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(values[0]))
+	}
+
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setField(slice.Index(i), []string{value}); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	return setValue(field, values[0])
+}
+
+func setValue(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Slice:
+		field.SetBytes([]byte(value))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return numError(err)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.Type() == durationType {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			field.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		field.SetFloat(n)
+	default:
+		return errors.New("unsupported field type " + field.Type().String())
+	}
+
+	return nil
+}
+
+func numError(err error) error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return numErr.Err
+	}
+	return err
+}
@@ -0,0 +1,268 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Codec registers a codec on the router, it is used by this router and every router
+// mounted under it. A codec with the same content type as an inherited one replaces it.
+func (r *Router) Codec(codec Codec) {
+	r.codecs = append(r.codecs, codec)
+}
+
+// Render encodes v with the codec picked by the request's Accept header. Codecs that
+// cannot encode v, like XML for maps, are skipped for the next acceptable one, and the
+// router's 500 is sent when none can.
+// When none of the registered codecs is acceptable the router's 406 error is sent as JSON.
+func (c *Context) Render(status int, v interface{}) {
+	codecs := c.router.negotiate(c.Request.Header.Get(header_accept))
+	if len(codecs) == 0 {
+		c.sendJSONError(http.StatusNotAcceptable)
+		return
+	}
+
+	var err error
+	for _, codec := range codecs {
+		// Encoded into a buffer first so nothing is written when the codec fails.
+		buffer := &bytes.Buffer{}
+		if err = codec.Encode(buffer, v); err != nil {
+			continue
+		}
+
+		c.Header(header_content_type, codec.ContentType())
+		c.Status(status)
+		c.Response.Write(buffer.Bytes())
+		return
+	}
+
+	RouterLogger.Err("Could not encode response as " + codecs[len(codecs) - 1].ContentType() + ":", err)
+	c.sendJSONError(http.StatusInternalServerError)
+}
+
+func (c *Context) sendJSONError(code int) {
+	err := c.router.Errors.Get(code)
+	c.Header(header_content_type, mime_json)
+	c.Status(err.Status).JSON(NewErrorResponse(err))
+}
+
+func (r *Router) codecList() []Codec {
+	chain := []*Router{}
+	for router := r; router != nil; router = router.parent {
+		chain = append(chain, router)
+	}
+
+	codecs := append([]Codec{}, DefaultCodecs...)
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, codec := range chain[i].codecs {
+			replaced := false
+			for j, n := range codecs {
+				if mediaType(n.ContentType()) == mediaType(codec.ContentType()) {
+					codecs[j], replaced = codec, true
+					break
+				}
+			}
+			if !replaced {
+				codecs = append(codecs, codec)
+			}
+		}
+	}
+
+	return codecs
+}
+
+// negotiate lists the acceptable codecs, most preferred first.
+func (r *Router) negotiate(accept string) []Codec {
+	codecs := r.codecList()
+	if strings.TrimSpace(accept) == "" {
+		return codecs
+	}
+
+	acceptable := []Codec{}
+	picked := map[string]bool{}
+	for _, accepted := range parseAccept(accept) {
+		for _, codec := range codecs {
+			offered := mediaType(codec.ContentType())
+			if !picked[offered] && mediaMatches(accepted.mediaType, offered) {
+				acceptable = append(acceptable, codec)
+				picked[offered] = true
+			}
+		}
+	}
+
+	return acceptable
+}
+
+func (r *Router) codecFor(contentType string) (Codec, bool) {
+	codecs := r.codecList()
+	if strings.TrimSpace(contentType) == "" {
+		return codecs[0], true
+	}
+
+	requested := mediaType(contentType)
+	for _, codec := range codecs {
+		if mediaType(codec.ContentType()) == requested {
+			return codec, true
+		}
+	}
+
+	return nil, false
+}
+
+func parseAccept(accept string) []mediaRange {
+	ranges := []mediaRange{}
+	for _, part := range strings.Split(accept, ",") {
+		accepted, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		ranges = append(ranges, mediaRange{accepted, quality})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+	return ranges
+}
+
+func mediaType(contentType string) string {
+	parsed, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return parsed
+}
+
+func mediaMatches(accepted, offered string) bool {
+	if accepted == "*/*" || accepted == offered {
+		return true
+	}
+	if strings.HasSuffix(accepted, "/*") {
+		return strings.HasPrefix(offered, strings.TrimSuffix(accepted, "*"))
+	}
+	return false
+}
+
+func (JSONCodec) ContentType() string {
+	return mime_json
+}
+
+func (JSONCodec) Encode(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func (JSONCodec) Decode(r io.Reader, v interface{}) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func (XMLCodec) ContentType() string {
+	return mime_xml
+}
+
+func (XMLCodec) Encode(w io.Writer, v interface{}) error {
+	return xml.NewEncoder(w).Encode(v)
+}
+
+func (XMLCodec) Decode(r io.Reader, v interface{}) error {
+	return xml.NewDecoder(r).Decode(v)
+}
+
+func (MsgPackCodec) ContentType() string {
+	return mime_msgpack
+}
+
+func (MsgPackCodec) Encode(w io.Writer, v interface{}) error {
+	encoder := msgpack.NewEncoder(w)
+	encoder.SetCustomStructTag("json")
+	return encoder.Encode(v)
+}
+
+func (MsgPackCodec) Decode(r io.Reader, v interface{}) error {
+	decoder := msgpack.NewDecoder(r)
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(v)
+}
+
+func (CBORCodec) ContentType() string {
+	return mime_cbor
+}
+
+func (CBORCodec) Encode(w io.Writer, v interface{}) error {
+	return cbor.NewEncoder(w).Encode(v)
+}
+
+func (CBORCodec) Decode(r io.Reader, v interface{}) error {
+	return cbor.NewDecoder(r).Decode(v)
+}
+
+func (TextCodec) ContentType() string {
+	return mime_text
+}
+
+// Encode writes the message of a Response or Error, anything else is printed as is.
+func (TextCodec) Encode(w io.Writer, v interface{}) error {
+	_, err := fmt.Fprintln(w, textMessage(v))
+	return err
+}
+
+func textMessage(v interface{}) interface{} {
+	switch message := v.(type) {
+	case Response:
+		return textMessage(message.Message)
+	case *Response:
+		if message != nil {
+			return textMessage(message.Message)
+		}
+	case Error:
+		return textMessage(message.Response)
+	case *Error:
+		if message != nil {
+			return textMessage(message.Response)
+		}
+	}
+	return v
+}
+
+func (TextCodec) Decode(r io.Reader, v interface{}) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	switch target := v.(type) {
+	case *string:
+		*target = string(b)
+	case *[]byte:
+		*target = b
+	default:
+		return errors.New("text body can only be decoded into a string or a byte slice")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiation(t *testing.T) {
+	sink := RouterLogger.sink
+	defer func() { RouterLogger.sink = sink }()
+	logs := &bytes.Buffer{}
+	RouterLogger.SetOutput(logs)
+
+	browser := "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+	server := NewServer(":0")
+	hello := func(ctx *Context) Response {
+		return NewSuccessResponse("hello")
+	}
+	server.Get("/string", hello)
+	server.Get("/map", func(ctx *Context) Response {
+		ctx.Render(http.StatusOK, map[string]int{"a": 1})
+		return NullResponse()
+	})
+	server.Set("/xml", NewRouter(func(r *Router) {
+		r.Codec(XMLCodec{})
+		r.Get("/string", hello)
+		r.Get("/map", func(ctx *Context) Response {
+			ctx.Render(http.StatusOK, map[string]int{"a": 1})
+			return NullResponse()
+		})
+	}))
+
+	handler := server.processRouterByDefault()
+	for _, n := range []struct {
+		path, accept string
+		status int
+		contentType string
+	}{
+		{"/string", "", http.StatusOK, mime_json},
+		{"/string", browser, http.StatusOK, mime_json},
+		{"/map", browser, http.StatusOK, mime_json},
+		{"/string", "text/plain", http.StatusOK, mime_text},
+		{"/string", "application/xml", http.StatusNotAcceptable, mime_json},
+		{"/string", "image/png", http.StatusNotAcceptable, mime_json},
+		{"/xml/string", browser, http.StatusOK, mime_xml},
+		{"/xml/map", browser, http.StatusOK, mime_json},
+		{"/xml/map", "application/xml", http.StatusInternalServerError, mime_json},
+	} {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, n.path, nil)
+		req.Header.Set(header_accept, n.accept)
+		handler.ServeHTTP(res, req)
+
+		if res.Code != n.status || res.Header().Get(header_content_type) != n.contentType {
+			t.Errorf("GET %s with Accept %q answered %d as %s: %s", n.path, n.accept, res.Code,
+				res.Header().Get(header_content_type), res.Body.String())
+		}
+		if n.status != http.StatusInternalServerError && logs.Len() > 0 {
+			t.Errorf("GET %s with Accept %q logged %s", n.path, n.accept, logs.String())
+		}
+		logs.Reset()
+	}
+}
@@ -1,9 +1,9 @@
 package core
 
 import (
-	"io/ioutil"
 	"encoding/json"
 	"github.com/gorilla/mux"
+	"net/http"
 )
 
 func NullResponse() Response {
@@ -49,9 +49,9 @@ func (c *Context) Header(key, val string) {
 }
 
 func (c *Context) SendError(err Error) {
-	if err == (Error{}) {
-		c.SendResponse(err.Status, Error{
-			&Response{
+	if err.Response == nil {
+		c.SendResponse(500, Error{
+			Response: &Response{
 				500,
 				"Unknown error",
 			},
@@ -67,7 +67,7 @@ func (c *Context) SendSuccess(message interface{}) {
 }
 
 func (c *Context) SendResponse(status int, message interface{}) {
-	c.Status(status).JSON(Response{
+	c.Render(status, Response{
 		status,
 		message,
 	})
@@ -84,19 +84,14 @@ func (c *Context) GetParams() map[string]string {
 }
 
 func (c *Context) GetBody(body interface{}) error {
-	b, err := ioutil.ReadAll(c.Request.Body)
 	defer c.Request.Body.Close()
 
-	if err != nil {
-		return err
+	codec, ok := c.router.codecFor(c.Request.Header.Get(header_content_type))
+	if !ok {
+		return c.router.Errors.Get(http.StatusUnsupportedMediaType)
 	}
 
-	err = json.Unmarshal(b, body)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return codec.Decode(c.Request.Body, body)
 }
 
 func (m *MiddlewareContext) Next() {
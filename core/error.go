@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 )
@@ -50,13 +51,26 @@ var HTTPErrors = getHTTPErrorMap(
 )
 
 func NewErrorMap(errs ...BindError) *ErrorMap {
-	errMap := HTTPErrors
+	return newErrorMap(HTTPErrors, errs...)
+}
+
+func newErrorMap(parent *ErrorMap, errs ...BindError) *ErrorMap {
+	errMap := &ErrorMap{
+		errors: map[int]*Error{},
+		parent: parent,
+	}
 	for _, n := range errs {
-		(*errMap)[n.Code] = NewError(n.Status, n.Message)
+		errMap.Set(n.Code, NewError(n.Err.Status, n.Err.Message))
 	}
 	return errMap
 }
 
+// Code returns an error that is answered with the error registered under code
+// in the ErrorMap of the router handling the request.
+func Code(code int) error {
+	return &Error{code: code}
+}
+
 func NewError(status int, message interface{}) *Error {
 	return &Error{
 		Response: &Response{
@@ -68,22 +82,50 @@ func NewError(status int, message interface{}) *Error {
 
 func Err(code int, status int, message string) BindError {
 	newErr := NewError(status, message)
+	newErr.code = code
 	return BindError{
 		Code: code,
-		Error: newErr,
+		Err: newErr,
+	}
+}
+
+// Error lets a BindError be returned from Result resolvers, it is sent with the
+// error registered under its code or its own status and message.
+func (b BindError) Error() string {
+	if b.Err == nil {
+		return "error " + strconv.Itoa(b.Code)
+	}
+	return b.Err.Error()
+}
+
+func (b BindError) Unwrap() error {
+	if b.Err == nil {
+		return nil
 	}
+	return b.Err
 }
 
 func getHTTPErrorMap(httpErrorCodes ...int) *ErrorMap {
-	httpErrors := &ErrorMap{}
+	httpErrors := newErrorMap(nil)
 	for _, n := range httpErrorCodes {
-		(*httpErrors)[n] = NewError(n, http.StatusText(n))
+		httpErrors.Set(n, NewError(n, http.StatusText(n)))
 	}
 	return httpErrors
 }
 
 func (e *Error) Send(ctx *Context) {
-	ctx.Status(e.Status).JSON(e)
+	ctx.Render(e.Status, e)
+}
+
+func (e *Error) Error() string {
+	if e.Response == nil {
+		return "error " + strconv.Itoa(e.code)
+	}
+	return fmt.Sprint(e.Message)
+}
+
+func (e *Error) WithDetail(detail string) *Error {
+	return NewError(e.Status, fmt.Sprint(e.Message, ": ", detail))
 }
 
 func (e *Error) SetMessage(mess interface{}) {
@@ -95,7 +137,7 @@ func (e *Error) SetStatus(status int) {
 }
 
 func (e *ErrorMap) Get(code int) *Error {
-	err, ok := (*e)[code]
+	err, ok := e.lookup(code)
 	if !ok {
 		RouterLogger.Warn("Missing error with code " + strconv.Itoa(code))
 		return &Error{}
@@ -103,6 +145,29 @@ func (e *ErrorMap) Get(code int) *Error {
 	return err
 }
 
+// Has reports whether code is set in this map or any map it falls back to.
+func (e *ErrorMap) Has(code int) bool {
+	_, ok := e.lookup(code)
+	return ok
+}
+
+func (e *ErrorMap) lookup(code int) (*Error, bool) {
+	for errMap := e; errMap != nil; errMap = errMap.parent {
+		if err, ok := errMap.errors[code]; ok {
+			return err, true
+		}
+	}
+	return nil, false
+}
+
+// Set only affects this map, routers it falls back to are left untouched. A copy of
+// err is stored, so the same *Error can be registered on several maps.
 func (e *ErrorMap) Set(code int, err *Error) {
-	(*e)[code] = err
+	stored := *err
+	if err.Response != nil {
+		response := *err.Response
+		stored.Response = &response
+	}
+	stored.code = code
+	e.errors[code] = &stored
 }
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSharedErrorOnTwoRouters(t *testing.T) {
+	shared := NewError(http.StatusTeapot, "I'm a teapot")
+
+	first := NewServer(":0")
+	first.Errors.Set(7, shared)
+	second := NewServer(":0")
+	second.Errors.Set(8, shared)
+	second.Errors.Set(9, HTTPErrors.Get(http.StatusBadRequest))
+
+	if err, ok := first.Errors.lookup(7); !ok || err.code != 7 {
+		t.Fatalf("first router lost code 7")
+	}
+	if err, ok := second.Errors.lookup(8); !ok || err.code != 8 {
+		t.Fatalf("second router lost code 8")
+	}
+	if shared.code != 0 || HTTPErrors.Get(http.StatusBadRequest).code != http.StatusBadRequest {
+		t.Fatalf("Set changed the code of the registered error")
+	}
+
+	first.Errors.Set(http.StatusBadRequest, NewError(http.StatusBadRequest, "Bad input"))
+	first.Get("/", Result(func(ctx *Context) (interface{}, error) {
+		return nil, HTTPErrors.Get(http.StatusBadRequest)
+	}))
+	res := serve(first.processRouterByDefault(), http.MethodGet, "/")
+	if res.Code != http.StatusBadRequest || !strings.Contains(res.Body.String(), "Bad input") {
+		t.Fatalf("GET / answered %d: %s", res.Code, res.Body.String())
+	}
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"strings"
+)
+
+// NotFound answers requests that match no route of this router or the routers
+// mounted under it without one of their own. By default they get the 404 of the
+// router's ErrorMap.
+func (r *Router) NotFound(resolver Resolver) {
+	r.notFound = resolver
+}
+
+// MethodNotAllowed answers requests whose path matches a route but whose method
+// does not. The Allow header is set before resolver is called.
+// OPTIONS is answered with 204 and HEAD by the GET route, when there is one.
+func (r *Router) MethodNotAllowed(resolver Resolver) {
+	r.methodNotAllowed = resolver
+}
+
+// setFallbacks makes the root router answer unmatched requests. Mounted routers do
+// not get mux handlers of their own, they would answer every request under their
+// prefix and hide routes registered after them. The resolvers and ErrorMap of the
+// deepest mounted router whose prefix matches are used instead.
+func (r *Router) setFallbacks() {
+	r.mux.NotFoundHandler = http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// mux forgets the method mismatch of a mounted route when a later route of the
+		// same router shares its prefix, so the path is tried with the other methods.
+		if methods := r.allowedMethods(req); len(methods) > 1 {
+			r.methodNotAllowedFallback(res, req, methods)
+			return
+		}
+
+		owner := r.owner(req)
+		owner.resolver(owner.notFoundResolver())(res, req)
+	})
+
+	r.mux.MethodNotAllowedHandler = http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		r.methodNotAllowedFallback(res, req, r.allowedMethods(req))
+	})
+}
+
+func (r *Router) methodNotAllowedFallback(res http.ResponseWriter, req *http.Request, methods []string) {
+	if r.serveImplicit(res, req, methods) {
+		return
+	}
+
+	res.Header().Set(header_allow, strings.Join(methods, ", "))
+	owner := r.owner(req)
+	owner.resolver(owner.methodNotAllowedResolver())(res, req)
+}
+
+// owner is the deepest router mounted under r whose prefix and host match req,
+// the first one mounted when several do.
+func (r *Router) owner(req *http.Request) *Router {
+	for _, child := range r.children {
+		if child.matcher.Match(req, &mux.RouteMatch{}) {
+			return child.owner(req)
+		}
+	}
+	return r
+}
+
+func (r *Router) notFoundResolver() Resolver {
+	for router := r; router != nil; router = router.parent {
+		if router.notFound != nil {
+			return router.notFound
+		}
+	}
+	return func(ctx *Context) Response {
+		return ctx.ErrorResponse(http.StatusNotFound, ctx.fallbackParams())
+	}
+}
+
+func (r *Router) methodNotAllowedResolver() Resolver {
+	for router := r; router != nil; router = router.parent {
+		if router.methodNotAllowed != nil {
+			return router.methodNotAllowed
+		}
+	}
+	return func(ctx *Context) Response {
+		return ctx.ErrorResponse(http.StatusMethodNotAllowed, ctx.fallbackParams())
+	}
+}
+
+func (r *Router) allowedMethods(req *http.Request) []string {
+	allowed := map[string]bool{}
+	for _, method := range routerMethods {
+		candidate := req.Clone(req.Context())
+		candidate.Method = method
+
+		match := &mux.RouteMatch{}
+		allowed[method] = r.mux.Match(candidate, match) && match.MatchErr == nil
+	}
+	allowed[http.MethodHead] = allowed[http.MethodHead] || allowed[http.MethodGet]
+	allowed[http.MethodOptions] = true
+
+	methods := []string{}
+	for _, method := range routerMethods {
+		if allowed[method] {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// fallbackParams can be used by translations of the 404 and 405, like
+// "Nicht gefunden: {{.path}}".
+func (c *Context) fallbackParams() Params {
+	return Params{"path": c.Request.URL.Path, "method": c.Request.Method}
+}
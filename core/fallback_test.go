@@ -0,0 +1,71 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serve(handler http.Handler, method, target string) *httptest.ResponseRecorder {
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(method, target, nil))
+	return res
+}
+
+func TestRoutesAfterSetOnSamePrefix(t *testing.T) {
+	server := NewServer(":0")
+	server.Set("/api", NewRouter(func(r *Router) {
+		r.Get("/users", Sender("users"))
+	}))
+	server.Get("/api/x", Sender("x"))
+
+	handler := server.processRouterByDefault()
+	if res := serve(handler, http.MethodGet, "/api/x"); res.Code != http.StatusOK || !strings.Contains(res.Body.String(), "x") {
+		t.Fatalf("GET /api/x answered %d: %s", res.Code, res.Body.String())
+	}
+	if res := serve(handler, http.MethodGet, "/api/users"); res.Code != http.StatusOK {
+		t.Fatalf("GET /api/users answered %d: %s", res.Code, res.Body.String())
+	}
+}
+
+func TestNotFoundUsesMountedRouterErrors(t *testing.T) {
+	server := NewServer(":0")
+	server.Set("/api", NewRouter(func(r *Router) {
+		r.Errors.Set(http.StatusNotFound, NewError(http.StatusNotFound, "no such api"))
+		r.Get("/users", Sender("users"))
+	}))
+
+	handler := server.processRouterByDefault()
+	if res := serve(handler, http.MethodGet, "/api/nope"); res.Code != http.StatusNotFound || !strings.Contains(res.Body.String(), "no such api") {
+		t.Fatalf("GET /api/nope answered %d: %s", res.Code, res.Body.String())
+	}
+	if res := serve(handler, http.MethodGet, "/nope"); res.Code != http.StatusNotFound || strings.Contains(res.Body.String(), "no such api") {
+		t.Fatalf("GET /nope answered %d: %s", res.Code, res.Body.String())
+	}
+
+	res := serve(handler, http.MethodDelete, "/api/users")
+	if res.Code != http.StatusMethodNotAllowed || res.Header().Get(header_allow) != "GET, HEAD, OPTIONS" {
+		t.Fatalf("DELETE /api/users answered %d with Allow %q", res.Code, res.Header().Get(header_allow))
+	}
+}
+
+func TestMethodNotAllowedBeforeLaterRoutes(t *testing.T) {
+	server := NewServer(":0")
+	server.Set("/api", NewRouter(func(r *Router) {
+		r.Get("/users", Sender("users"))
+		r.Post("/orders", Sender("orders"))
+	}))
+
+	handler := server.processRouterByDefault()
+	res := serve(handler, http.MethodDelete, "/api/users")
+	if res.Code != http.StatusMethodNotAllowed || res.Header().Get(header_allow) != "GET, HEAD, OPTIONS" {
+		t.Fatalf("DELETE /api/users answered %d with Allow %q", res.Code, res.Header().Get(header_allow))
+	}
+	if res := serve(handler, http.MethodHead, "/api/users"); res.Code != http.StatusOK || res.Body.Len() != 0 {
+		t.Fatalf("HEAD /api/users answered %d: %s", res.Code, res.Body.String())
+	}
+	if res := serve(handler, http.MethodGet, "/api/nope"); res.Code != http.StatusNotFound {
+		t.Fatalf("GET /api/nope answered %d: %s", res.Code, res.Body.String())
+	}
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"fmt"
+	"github.com/gorilla/mux"
+	"reflect"
+)
+
+// Group mounts a router at prefix and hands it to init. Middleware used on the group
+// only runs for its routes. GroupConfig limits the group to a host and prefixes the
+// names of its routes.
+func (r *Router) Group(prefix string, init func(g *Router), config ...GroupConfig) *Router {
+	groupConfig := GroupConfig{}
+	if len(config) > 0 {
+		groupConfig = config[0]
+	}
+
+	group := r.mount(prefix, groupConfig)
+	if init != nil {
+		init(group)
+	}
+	return group
+}
+
+func (r *Router) mount(prefix string, config GroupConfig) *Router {
+	route := r.mux.PathPrefix(prefix)
+	if config.Host != "" {
+		route = route.Host(config.Host)
+	}
+
+	router := &Router{
+		mux: route.Subrouter(),
+		Errors: newErrorMap(r.Errors),
+		parent: r,
+		namePrefix: r.namePrefix + config.Name,
+		matcher: prefixMatcher(route),
+	}
+	r.children = append(r.children, router)
+	return router
+}
+
+// prefixMatcher matches the full prefix and host of route on its own, without the
+// routes of the router mounted there.
+func prefixMatcher(route *mux.Route) *mux.Route {
+	matcher := mux.NewRouter().NewRoute()
+	if prefix, err := route.GetPathTemplate(); err == nil {
+		matcher.PathPrefix(prefix)
+	}
+	if host, err := route.GetHostTemplate(); err == nil {
+		matcher.Host(host)
+	}
+	return matcher
+}
+
+func routerInit(router interface{}) (func(r *Router), error) {
+	if initRouter, ok := router.(*InitRouter); ok {
+		if initRouter == nil || initRouter.Init == nil {
+			return nil, fmt.Errorf("%w: NewRouter was given no init function", ErrInvalidRouter)
+		}
+		return func(r *Router) {
+			initRouter.Router = r
+			initRouter.Init(r)
+		}, nil
+	}
+
+	routerValue := reflect.ValueOf(router)
+	if routerValue.Kind() != reflect.Ptr || routerValue.IsNil() || routerValue.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %T is not a pointer to a struct", ErrInvalidRouter, router)
+	}
+
+	field := routerValue.Elem().FieldByName(router_field_name)
+	if !field.IsValid() || !field.CanSet() || field.Type() != reflect.TypeOf(&Router{}) {
+		return nil, fmt.Errorf("%w: %T does not embed *core.Router", ErrInvalidRouter, router)
+	}
+	setRouter := func(r *Router) {
+		field.Set(reflect.ValueOf(r))
+	}
+
+	if method := routerValue.MethodByName(router_init_func_name); method.IsValid() {
+		init, ok := method.Interface().(func())
+		if !ok {
+			return nil, fmt.Errorf("%w: %T.Init must be a func()", ErrInvalidRouter, router)
+		}
+		return func(r *Router) {
+			setRouter(r)
+			init()
+		}, nil
+	}
+
+	initField := routerValue.Elem().FieldByName(router_init_func_name)
+	if !initField.IsValid() || !initField.CanInterface() {
+		return nil, fmt.Errorf("%w: %T has no Init method or field", ErrInvalidRouter, router)
+	}
+	init, ok := initField.Interface().(func(r *Router))
+	if !ok || init == nil {
+		return nil, fmt.Errorf("%w: %T.Init must be a non-nil func(*core.Router)", ErrInvalidRouter, router)
+	}
+	return func(r *Router) {
+		setRouter(r)
+		init(r)
+	}, nil
+}
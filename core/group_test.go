@@ -0,0 +1,34 @@
+package core
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGroupsOnSamePrefix(t *testing.T) {
+	server := NewServer(":0")
+	server.Group("/api", func(g *Router) {
+		g.Get("/public", Sender("public"))
+	})
+	server.Group("/api", func(g *Router) {
+		g.Use(func(ctx *MiddlewareContext) {
+			ctx.Header("X-Authed", "1")
+			ctx.Next()
+		})
+		g.Get("/private", Sender("private"))
+	})
+	server.Group("", func(g *Router) {
+		g.Get("/root", Sender("root"))
+	})
+	server.Get("/after", Sender("after"))
+
+	handler := server.processRouterByDefault()
+	for _, path := range []string{"/api/public", "/api/private", "/root", "/after"} {
+		if res := serve(handler, http.MethodGet, path); res.Code != http.StatusOK {
+			t.Errorf("GET %s answered %d: %s", path, res.Code, res.Body.String())
+		}
+	}
+	if res := serve(handler, http.MethodGet, "/api/public"); res.Header().Get("X-Authed") != "" {
+		t.Errorf("middleware of the second group ran for the first")
+	}
+}
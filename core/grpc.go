@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// GRPC registers gRPC services on the server's own listener. HTTP/2 requests with an
+// application/grpc Content-Type are served by them, everything else by the routers.
+// Plaintext HTTP/2 is accepted through h2c. opts are used by the first call only,
+// which creates the gRPC server with the logging and recovery interceptors.
+func (s *Server) GRPC(register func(g *grpc.Server), opts ...grpc.ServerOption) {
+	if s.grpcServer == nil {
+		opts = append([]grpc.ServerOption{
+			grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+			grpc.ChainStreamInterceptor(s.streamInterceptor),
+		}, opts...)
+		s.grpcServer = grpc.NewServer(opts...)
+	}
+	register(s.grpcServer)
+}
+
+func (s *Server) grpcHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.ProtoMajor == 2 && strings.HasPrefix(req.Header.Get(header_content_type), mime_grpc) {
+			s.grpcServer.ServeHTTP(res, req)
+			return
+		}
+		next.ServeHTTP(res, req)
+	})
+}
+
+func (s *Server) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+	profiler := TrackTime()
+	defer func() {
+		if s.Recovery {
+			if recovered := recover(); recovered != nil {
+				err = s.recoverRPC(info.FullMethod, recovered, debug.Stack())
+			}
+		}
+		s.logRPC(info.FullMethod, err, profiler)
+	}()
+
+	return handler(ctx, req)
+}
+
+func (s *Server) streamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	profiler := TrackTime()
+	defer func() {
+		if s.Recovery {
+			if recovered := recover(); recovered != nil {
+				err = s.recoverRPC(info.FullMethod, recovered, debug.Stack())
+			}
+		}
+		s.logRPC(info.FullMethod, err, profiler)
+	}()
+
+	return handler(srv, stream)
+}
+
+// recoverRPC answers panics with codes.Internal and the message of the server's 500 error.
+// gRPC services are registered on the server, not on a router, so its ErrorMap owns them.
+func (s *Server) recoverRPC(method string, recovered interface{}, stack []byte) error {
+	MainLogger.Err("Recovered from panic in gRPC", method + ":", recovered, "\n" + string(stack))
+
+	message := fmt.Sprint(s.Router.internalError().Message)
+	if s.Debug {
+		message += ": " + fmt.Sprint(recovered)
+	}
+	return status.Error(codes.Internal, message)
+}
+
+func (s *Server) logRPC(method string, err error, profiler func() time.Duration) {
+	if s.Debug {
+		RequestLogger.Info("GRPC", method, status.Code(err),
+			"ms -", float32(profiler().Nanoseconds()) / float32(1000000))
+	}
+}
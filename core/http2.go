@@ -0,0 +1,36 @@
+package core
+
+import (
+	"errors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"net/http"
+)
+
+// Push starts an HTTP/2 server push of path. Nothing happens when the connection
+// does not support push, errors are only returned for failed pushes.
+func (c *Context) Push(path string) error {
+	err := c.writer.Push(path, nil)
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
+
+// configureHTTP2 applies the HTTP2 config to TLS connections of NativeServer and
+// serves plaintext HTTP/2 through h2c when H2C is set or gRPC services are registered.
+func (s *Server) configureHTTP2(handler http.Handler) http.Handler {
+	h2Server := &http2.Server{
+		MaxConcurrentStreams: s.HTTP2.MaxConcurrentStreams,
+		MaxReadFrameSize: s.HTTP2.MaxReadFrameSize,
+		IdleTimeout: s.HTTP2.IdleTimeout,
+	}
+	if err := http2.ConfigureServer(s.NativeServer, h2Server); err != nil {
+		s.onError(err)
+	}
+
+	if s.HTTP2.H2C || s.grpcServer != nil {
+		return h2c.NewHandler(handler, h2Server)
+	}
+	return handler
+}
@@ -0,0 +1,246 @@
+package core
+
+import (
+	"encoding/json"
+	"github.com/gorilla/websocket"
+)
+
+func NewHub() *Hub {
+	return &Hub{
+		SendBufferSize: hub_send_buffer_size,
+		clients: map[string]*HubClient{},
+		rooms: map[string]map[string]*HubClient{},
+	}
+}
+
+func (h *Hub) Handler(resolver HubResolver) WebSocketResolver {
+	return func(ctx *WebSocketContext) {
+		client, ok := h.register(ctx)
+		if !ok {
+			ctx.Close(websocket.CloseGoingAway, hub_shutdown_reason)
+			return
+		}
+		defer h.unregister(client)
+
+		go client.writePump()
+
+		resolver(client)
+	}
+}
+
+func (h *Hub) OnPresence(onPresence func(event PresenceEvent)) {
+	h.mutex.Lock()
+	h.onPresence = onPresence
+	h.mutex.Unlock()
+}
+
+func (h *Hub) Client(id string) (*HubClient, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	client, ok := h.clients[id]
+	return client, ok
+}
+
+func (h *Hub) Members(room string) []string {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	members := make([]string, 0, len(h.rooms[room]))
+	for id := range h.rooms[room] {
+		members = append(members, id)
+	}
+	return members
+}
+
+func (h *Hub) Send(id string, messageType int, data []byte) bool {
+	client, ok := h.Client(id)
+	if !ok {
+		return false
+	}
+	return client.Send(messageType, data)
+}
+
+// SendJSON fails with ErrUnknownClient when no client has id and with ErrClientGone
+// when the client is closed or was dropped for being too slow.
+func (h *Hub) SendJSON(id string, v interface{}) error {
+	client, ok := h.Client(id)
+	if !ok {
+		return ErrUnknownClient
+	}
+	return client.SendJSON(v)
+}
+
+func (h *Hub) Broadcast(room string, messageType int, data []byte) {
+	h.mutex.RLock()
+	clients := make([]*HubClient, 0, len(h.rooms[room]))
+	for _, client := range h.rooms[room] {
+		clients = append(clients, client)
+	}
+	h.mutex.RUnlock()
+
+	for _, client := range clients {
+		client.Send(messageType, data)
+	}
+}
+
+func (h *Hub) BroadcastJSON(room string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	h.Broadcast(room, websocket.TextMessage, data)
+	return nil
+}
+
+func (h *Hub) Shutdown(code int, reason string) {
+	h.mutex.Lock()
+	h.closed = true
+	clients := make([]*HubClient, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mutex.Unlock()
+
+	for _, client := range clients {
+		client.Close(code, reason)
+	}
+}
+
+func (h *Hub) register(ctx *WebSocketContext) (*HubClient, bool) {
+	client := &HubClient{
+		WebSocketContext: ctx,
+		ID: RandomID(),
+		hub: h,
+		send: make(chan hubMessage, h.SendBufferSize),
+		rooms: map[string]bool{},
+	}
+
+	h.mutex.Lock()
+	if h.closed {
+		h.mutex.Unlock()
+		return nil, false
+	}
+	h.clients[client.ID] = client
+	h.mutex.Unlock()
+
+	h.presence(PresenceConnect, "", client)
+	return client, true
+}
+
+func (h *Hub) unregister(client *HubClient) {
+	for _, room := range client.Rooms() {
+		client.Leave(room)
+	}
+
+	h.mutex.Lock()
+	delete(h.clients, client.ID)
+	h.mutex.Unlock()
+
+	h.presence(PresenceDisconnect, "", client)
+}
+
+func (h *Hub) presence(eventType PresenceEventType, room string, client *HubClient) {
+	h.mutex.RLock()
+	onPresence := h.onPresence
+	h.mutex.RUnlock()
+
+	if onPresence != nil {
+		onPresence(PresenceEvent{
+			Type: eventType,
+			Room: room,
+			Client: client,
+		})
+	}
+}
+
+func (c *HubClient) Join(room string) {
+	c.hub.mutex.Lock()
+	if c.rooms[room] {
+		c.hub.mutex.Unlock()
+		return
+	}
+	if c.hub.rooms[room] == nil {
+		c.hub.rooms[room] = map[string]*HubClient{}
+	}
+	c.hub.rooms[room][c.ID] = c
+	c.rooms[room] = true
+	c.hub.mutex.Unlock()
+
+	c.hub.presence(PresenceJoin, room, c)
+}
+
+func (c *HubClient) Leave(room string) {
+	c.hub.mutex.Lock()
+	if !c.rooms[room] {
+		c.hub.mutex.Unlock()
+		return
+	}
+	delete(c.hub.rooms[room], c.ID)
+	if len(c.hub.rooms[room]) == 0 {
+		delete(c.hub.rooms, room)
+	}
+	delete(c.rooms, room)
+	c.hub.mutex.Unlock()
+
+	c.hub.presence(PresenceLeave, room, c)
+}
+
+func (c *HubClient) Rooms() []string {
+	c.hub.mutex.RLock()
+	defer c.hub.mutex.RUnlock()
+
+	rooms := make([]string, 0, len(c.rooms))
+	for room := range c.rooms {
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+// Send queues a message without blocking. When the connection's buffer is full
+// the client is too slow to keep up, so it is disconnected and false is returned.
+func (c *HubClient) Send(messageType int, data []byte) bool {
+	select {
+	case <-c.Done():
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- hubMessage{messageType, data}:
+		return true
+	default:
+		RouterLogger.Warn("Dropping slow WebSocket client", c.ID)
+		go c.Close(websocket.CloseTryAgainLater, "send buffer full")
+		return false
+	}
+}
+
+func (c *HubClient) SendJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if !c.Send(websocket.TextMessage, data) {
+		return ErrClientGone
+	}
+	return nil
+}
+
+func (c *HubClient) SendText(text string) bool {
+	return c.Send(websocket.TextMessage, []byte(text))
+}
+
+func (c *HubClient) writePump() {
+	for {
+		select {
+		case message := <-c.send:
+			if err := c.WriteMessage(message.messageType, message.data); err != nil {
+				c.Close(websocket.CloseInternalServerErr, "")
+				return
+			}
+		case <-c.Done():
+			return
+		}
+	}
+}
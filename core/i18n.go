@@ -0,0 +1,220 @@
+package core
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Messages registers translations of error codes for a language like "de" or "pt-BR".
+// A message is a text/template filled with the Params given when the error is sent.
+// Routers mounted under this one inherit the catalog and can override single codes.
+func (r *Router) Messages(language string, messages map[int]string) {
+	if r.catalogs == nil {
+		r.catalogs = map[string]map[int]*template.Template{}
+	}
+
+	language = strings.ToLower(language)
+	if r.catalogs[language] == nil {
+		r.catalogs[language] = map[int]*template.Template{}
+	}
+
+	for code, message := range messages {
+		name := language + "/" + strconv.Itoa(code)
+		messageTemplate, err := template.New(name).Option("missingkey=zero").Parse(message)
+		if err != nil {
+			RouterLogger.Err("Could not parse message " + name + ":", err)
+			continue
+		}
+		r.catalogs[language][code] = messageTemplate
+	}
+}
+
+// ErrorResponse is NewErrorResponse for the error registered under code,
+// with its message translated to the request's Accept-Language.
+// Codes missing from the ErrorMap get the 500, like in SendErrorCode.
+func (c *Context) ErrorResponse(code int, params ...Params) Response {
+	err := c.localize(c.router.Errors.Get(code), code, params...)
+	if err.Response == nil {
+		err = c.localize(c.router.Errors.Get(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+	if err.Response == nil {
+		err = NewError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+	return NewErrorResponse(err)
+}
+
+func (c *Context) SendErrorCode(code int, params ...Params) {
+	err := c.localize(c.router.Errors.Get(code), code, params...)
+	if err.Response == nil {
+		c.SendError(Error{})
+		return
+	}
+	err.Send(c)
+}
+
+// Languages are the languages of the Accept-Language header, most preferred first.
+// A regional language like "de-at" is followed by its base language "de".
+func (c *Context) Languages() []string {
+	return parseAcceptLanguage(c.Request.Header.Get(header_accept_language))
+}
+
+func (c *Context) localize(err *Error, code int, params ...Params) *Error {
+	if err.Response == nil {
+		return err
+	}
+
+	data := Params{}
+	for _, n := range params {
+		for key, value := range n {
+			data[key] = value
+		}
+	}
+
+	for _, language := range c.Languages() {
+		for router := c.router; router != nil; router = router.parent {
+			if messageTemplate, ok := router.catalogs[language][code]; ok {
+				return renderMessage(err, messageTemplate, data)
+			}
+		}
+	}
+
+	// Without a translation the ErrorMap message itself is the template.
+	if message, ok := err.Message.(string); ok && len(data) > 0 && strings.Contains(message, "{{") {
+		messageTemplate, parseErr := template.New(strconv.Itoa(code)).Option("missingkey=zero").Parse(message)
+		if parseErr == nil {
+			return renderMessage(err, messageTemplate, data)
+		}
+	}
+
+	return err
+}
+
+func renderMessage(err *Error, messageTemplate *template.Template, data Params) *Error {
+	message := &strings.Builder{}
+	if templateErr := messageTemplate.Execute(message, withMissingKeys(messageTemplate, data)); templateErr != nil {
+		RouterLogger.Err("Could not render message " + messageTemplate.Name() + ":", templateErr)
+		return err
+	}
+
+	localized := NewError(err.Status, message.String())
+	localized.code = err.code
+	return localized
+}
+
+// withMissingKeys sets the fields messageTemplate uses but data lacks to "". With
+// Params being a map, missingkey=zero would still print them as "<no value>".
+func withMissingKeys(messageTemplate *template.Template, data Params) Params {
+	fields := map[string]bool{}
+	if messageTemplate.Tree != nil {
+		templateFields(messageTemplate.Tree.Root, fields)
+	}
+
+	filled := Params{}
+	for field := range fields {
+		filled[field] = ""
+	}
+	for key, value := range data {
+		filled[key] = value
+	}
+	return filled
+}
+
+func templateFields(node parse.Node, fields map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			templateFields(child, fields)
+		}
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, command := range n.Cmds {
+			templateFields(command, fields)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			templateFields(arg, fields)
+		}
+	case *parse.ActionNode:
+		templateFields(n.Pipe, fields)
+	case *parse.TemplateNode:
+		templateFields(n.Pipe, fields)
+	case *parse.ChainNode:
+		templateFields(n.Node, fields)
+	case *parse.FieldNode:
+		fields[n.Ident[0]] = true
+	case *parse.IfNode:
+		templateBranchFields(&n.BranchNode, fields)
+	case *parse.RangeNode:
+		templateBranchFields(&n.BranchNode, fields)
+	case *parse.WithNode:
+		templateBranchFields(&n.BranchNode, fields)
+	}
+}
+
+func templateBranchFields(branch *parse.BranchNode, fields map[string]bool) {
+	templateFields(branch.Pipe, fields)
+	templateFields(branch.List, fields)
+	templateFields(branch.ElseList, fields)
+}
+
+func parseAcceptLanguage(acceptLanguage string) []string {
+	type weightedLanguage struct {
+		language string
+		quality float64
+	}
+
+	weighted := []weightedLanguage{}
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		language := strings.ToLower(strings.TrimSpace(fields[0]))
+		if language == "" || language == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if parsed, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		weighted = append(weighted, weightedLanguage{language, quality})
+	}
+
+	sort.SliceStable(weighted, func(i, j int) bool {
+		return weighted[i].quality > weighted[j].quality
+	})
+
+	languages := []string{}
+	seen := map[string]bool{}
+	for _, n := range weighted {
+		candidates := []string{n.language}
+		if i := strings.Index(n.language, "-"); i > 0 {
+			candidates = append(candidates, n.language[:i])
+		}
+
+		for _, language := range candidates {
+			if !seen[language] {
+				seen[language] = true
+				languages = append(languages, language)
+			}
+		}
+	}
+
+	return languages
+}
@@ -0,0 +1,35 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLocalizedNotFound(t *testing.T) {
+	server := NewServer(":0")
+	server.Messages("de", map[int]string{
+		http.StatusNotFound: "Nicht gefunden: {{.path}}",
+		1: "Feld {{.field}} fehlt{{if .min}}, mindestens {{.min}}{{end}}",
+	})
+	server.Errors.Set(1, NewError(http.StatusBadRequest, "Missing field"))
+	server.Get("/code", Result(func(ctx *Context) (interface{}, error) {
+		return nil, Code(1)
+	}))
+
+	handler := server.processRouterByDefault()
+	for path, want := range map[string]string{
+		"/nope": "Nicht gefunden: /nope",
+		"/code": "Feld  fehlt",
+	} {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(header_accept_language, "de-DE,de;q=0.9")
+		handler.ServeHTTP(res, req)
+
+		if body := res.Body.String(); !strings.Contains(body, `"`+want+`"`) || strings.Contains(body, "<no value>") {
+			t.Errorf("GET %s answered %d: %s", path, res.Code, body)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+func NewJSONRPC() *JSONRPCRegistry {
+	return &JSONRPCRegistry{methods: map[string]jsonRPCMethod{}}
+}
+
+// RegisterRPC adds method to registry. Params given by name are decoded into P,
+// params given by position into the fields of P in order, or into P itself when it
+// is a slice. They are checked against their `validate` tags before fn is called.
+func RegisterRPC[P any, R any](registry *JSONRPCRegistry, method string, fn func(ctx *Context, params P) (R, error)) {
+	registry.methods[method] = func(ctx *Context, raw json.RawMessage) (interface{}, error) {
+		var params P
+		if err := decodeRPCParams(raw, &params); err != nil {
+			return nil, &JSONRPCError{Code: JSONRPCInvalidParams, Message: "Invalid params", Data: err.Error()}
+		}
+		if err := ctx.Validate(&params); err != nil {
+			return nil, &JSONRPCError{Code: JSONRPCInvalidParams, Message: "Invalid params", Data: err}
+		}
+		return fn(ctx, params)
+	}
+}
+
+// JSONRPC serves registry as JSON-RPC 2.0 over POST requests to path.
+func (r *Router) JSONRPC(path string, registry *JSONRPCRegistry) *Route {
+	return r.Post(path, registry.resolve)
+}
+
+// ServeWS answers every text message of a WebSocket connection like a JSON-RPC
+// request body, use it as resolver of Router.WS.
+func (j *JSONRPCRegistry) ServeWS(ctx *WebSocketContext) {
+	for {
+		messageType, data, err := ctx.ReadMessage()
+		if err != nil {
+			if !IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				RouterLogger.Warn("JSON-RPC WebSocket closed:", err)
+			}
+			return
+		}
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		if out := j.handle(ctx.Context, data); out != nil {
+			if err := ctx.WriteMessage(websocket.TextMessage, out); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (e *JSONRPCError) Error() string {
+	return fmt.Sprintf("json-rpc error %d: %s", e.Code, e.Message)
+}
+
+func (j *JSONRPCRegistry) resolve(ctx *Context) Response {
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return NewErrorResponse(ctx.router.Errors.Get(http.StatusBadRequest).WithDetail(err.Error()))
+	}
+
+	out := j.handle(ctx, body)
+	if out == nil {
+		ctx.Status(http.StatusNoContent)
+		return NullResponse()
+	}
+
+	ctx.Header(header_content_type, mime_json)
+	ctx.Status(http.StatusOK)
+	ctx.Response.Write(out)
+	return NullResponse()
+}
+
+// handle answers a single request or a batch, nil when there is nothing to answer
+// because only notifications were sent.
+func (j *JSONRPCRegistry) handle(ctx *Context, body []byte) []byte {
+	body = bytes.TrimSpace(body)
+
+	if len(body) > 0 && body[0] == '[' {
+		batch := []json.RawMessage{}
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return encodeRPC(rpcErrorResponse(nil, &JSONRPCError{Code: JSONRPCParseError, Message: "Parse error"}))
+		}
+		if len(batch) == 0 {
+			return encodeRPC(rpcErrorResponse(nil, &JSONRPCError{Code: JSONRPCInvalidRequest, Message: "Invalid Request"}))
+		}
+
+		responses := []jsonRPCResponse{}
+		for _, n := range batch {
+			if response := j.call(ctx, n); response != nil {
+				responses = append(responses, *response)
+			}
+		}
+		if len(responses) == 0 {
+			return nil
+		}
+		return encodeRPC(responses)
+	}
+
+	if response := j.call(ctx, body); response != nil {
+		return encodeRPC(response)
+	}
+	return nil
+}
+
+func (j *JSONRPCRegistry) call(ctx *Context, raw json.RawMessage) *jsonRPCResponse {
+	req := jsonRPCRequest{}
+	if err := json.Unmarshal(raw, &req); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return rpcErrorResponse(nil, &JSONRPCError{Code: JSONRPCParseError, Message: "Parse error"})
+		}
+		return rpcErrorResponse(nil, &JSONRPCError{Code: JSONRPCInvalidRequest, Message: "Invalid Request"})
+	}
+	if req.JSONRPC != jsonrpc_version || req.Method == "" {
+		return rpcErrorResponse(req.ID, &JSONRPCError{Code: JSONRPCInvalidRequest, Message: "Invalid Request"})
+	}
+
+	// Requests without an id are notifications and get no response, not even errors.
+	notification := req.ID == nil
+
+	method, ok := j.methods[req.Method]
+	if !ok {
+		if notification {
+			return nil
+		}
+		return rpcErrorResponse(req.ID, &JSONRPCError{Code: JSONRPCMethodNotFound, Message: "Method not found"})
+	}
+
+	result, err := method(ctx, req.Params)
+	if notification {
+		return nil
+	}
+	if err != nil {
+		return rpcErrorResponse(req.ID, ctx.jsonRPCError(err))
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return rpcErrorResponse(req.ID, ctx.jsonRPCError(err))
+	}
+	return &jsonRPCResponse{JSONRPC: jsonrpc_version, Result: encoded, ID: req.ID}
+}
+
+// jsonRPCError sends errors with an ErrorMap code under that code and every other
+// error as Internal error with the message of the ErrorMap's 500.
+func (c *Context) jsonRPCError(err error) *JSONRPCError {
+	var rpcErr *JSONRPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+
+	mapped, ok := c.router.mapError(err)
+	if !ok || mapped.code == 0 {
+		translated := c.router.translateError(err)
+		return &JSONRPCError{Code: JSONRPCInternalError, Message: fmt.Sprint(translated.Message)}
+	}
+
+	mapped = c.localize(mapped, mapped.code)
+	return &JSONRPCError{Code: mapped.code, Message: fmt.Sprint(mapped.Message)}
+}
+
+func rpcErrorResponse(id json.RawMessage, err *JSONRPCError) *jsonRPCResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &jsonRPCResponse{JSONRPC: jsonrpc_version, Error: err, ID: id}
+}
+
+func encodeRPC(v interface{}) []byte {
+	out, err := json.Marshal(v)
+	if err != nil {
+		RouterLogger.Err("Could not encode JSON-RPC response:", err)
+		return nil
+	}
+	return out
+}
+
+func decodeRPCParams(raw json.RawMessage, params interface{}) error {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+
+	value := reflect.ValueOf(params).Elem()
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+
+	if raw[0] != '[' || value.Kind() != reflect.Struct {
+		return json.Unmarshal(raw, value.Addr().Interface())
+	}
+
+	positional := []json.RawMessage{}
+	if err := json.Unmarshal(raw, &positional); err != nil {
+		return err
+	}
+
+	fields := []reflect.Value{}
+	for i := 0; i < value.NumField(); i++ {
+		if value.Type().Field(i).IsExported() {
+			fields = append(fields, value.Field(i))
+		}
+	}
+	if len(positional) > len(fields) {
+		return fmt.Errorf("expected at most %d params, got %d", len(fields), len(positional))
+	}
+
+	for i, n := range positional {
+		if err := json.Unmarshal(n, fields[i].Addr().Interface()); err != nil {
+			return fmt.Errorf("param %d: %w", i, err)
+		}
+	}
+	return nil
+}
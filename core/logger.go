@@ -1,94 +1,135 @@
 package core
 
 import (
-	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"runtime"
 	"strconv"
+	"strings"
 	"time"
 )
 
 func NewLogger(name string) *Logger {
 	return &Logger{
 		Name: name,
-		writer: fmt.Print,
-		writerf: fmt.Printf,
-		writerln: fmt.Println,
+		Level: LevelNote,
+		Format: FormatText,
+		sink: &logSink{
+			writer: os.Stdout,
+			ansi: true,
+		},
 	}
 }
 
-func (l *Logger) LogIntoFile(filePath string, ) *Logger {
-	logFile, err := os.Create(filePath + "-" + time.Now().Format("2006-01-02") + ".log")
-	if err != nil {
+// LogIntoFile writes into <filePath>-<date>.log, starting a new file every day.
+func (l *Logger) LogIntoFile(filePath string) *Logger {
+	return l.LogIntoRotatingFile(NewRotatingFile(filePath))
+}
+
+// LogIntoRotatingFile writes into file, which is reopened on SIGHUP.
+// The same file can be shared by several loggers.
+func (l *Logger) LogIntoRotatingFile(file *RotatingFile) *Logger {
+	if err := file.Open(); err != nil {
 		l.Err("Could not create new log file, logging into terminal")
 		return l
 	}
+	file.ReopenOnSignal()
 
-	l.fileWriter = bufio.NewWriter(logFile)
+	l.logFile = file
+	l.sink = &logSink{writer: file}
+	return l
+}
 
-	l.writer = func(a ...interface{}) (n int, err error) {
-		num, err := fmt.Fprint(l.fileWriter, a...)
-		return num, err
+func (l *Logger) Close() error {
+	if l.logFile == nil {
+		return nil
 	}
-	l.writerf = func(format string, a ...interface{}) (n int, err error) {
-		num, err := fmt.Fprintf(l.fileWriter, format, a...)
-		return num, err
-	}
-	l.writerln = func(a ...interface{}) (n int, err error) {
-		num, err := fmt.Fprintln(l.fileWriter, a...)
-		return num, err
-	}
-
-	l.logFile = logFile
+	return l.logFile.Close()
+}
 
-	l.logIntoFile = true
+// SetOutput sends this logger's lines to w, loggers created from it with With
+// before the call keep their previous output.
+func (l *Logger) SetOutput(w io.Writer) *Logger {
+	l.sink = &logSink{writer: w}
 	return l
 }
 
+// With returns a child logger that adds the field to every line it writes.
+func (l *Logger) With(key string, value interface{}) *Logger {
+	child := *l
+	child.fields = append(append([]logField{}, l.fields...), logField{key, value})
+	return &child
+}
+
 func (l *Logger) Info(mess ...interface{}) {
-	l.log(tag_info, []int{36, 1}, false, "", mess...)
+	l.log(LevelInfo, tag_info, []int{36, 1}, false, "", mess...)
 }
 
 func (l *Logger) Infof(format string, mess ...interface{}) {
-	l.log(tag_info, []int{36, 1}, true, format, mess...)
+	l.log(LevelInfo, tag_info, []int{36, 1}, true, format, mess...)
 }
 
 func (l *Logger) Warn(mess ...interface{}) {
-	l.log(tag_warn, []int{33, 1}, false, "", mess...)
+	l.log(LevelWarn, tag_warn, []int{33, 1}, false, "", mess...)
 }
 
 func (l *Logger) Warnf(format string, mess ...interface{}) {
-	l.log(tag_warn, []int{33, 1}, true, format, mess...)
+	l.log(LevelWarn, tag_warn, []int{33, 1}, true, format, mess...)
 }
 
 func (l *Logger) Err(mess ...interface{}) {
-	l.log(tag_error, []int{31, 1}, false, "", mess...)
+	l.log(LevelError, tag_error, []int{31, 1}, false, "", mess...)
 }
 
 func (l *Logger) Errf(format string, mess ...interface{}) {
-	l.log(tag_error, []int{31, 1}, true, format, mess...)
+	l.log(LevelError, tag_error, []int{31, 1}, true, format, mess...)
 }
 
 func (l *Logger) Note(mess ...interface{}) {
-	l.log(tag_note, []int{34, 1}, false, "", mess...)
+	l.log(LevelNote, tag_note, []int{34, 1}, false, "", mess...)
 }
 
 func (l *Logger) Notef(format string, mess ...interface{}) {
-	l.log(tag_note, []int{34, 1}, true, format, mess...)
+	l.log(LevelNote, tag_note, []int{34, 1}, true, format, mess...)
 }
 
 func (l *Logger) Log(mess ...interface{}) {
-	l.log("", []int{}, false, "", mess...)
+	l.log(LevelInfo, "", []int{}, false, "", mess...)
 }
 
 func (l *Logger) Logf(format string, mess ...interface{}) {
-	l.log("", []int{}, true, format, mess...)
+	l.log(LevelInfo, "", []int{}, true, format, mess...)
 }
 
-func (l *Logger) log(tag string, tagColor []int, format bool, formatString string, mess ...interface{}) {
+func (l *Logger) log(level LogLevel, tag string, tagColor []int, format bool, formatString string, mess ...interface{}) {
+	if len(tag) > 0 && level < l.Level {
+		return
+	}
+
+	var message string
+	if format {
+		message = fmt.Sprintf(formatString, mess...)
+	} else {
+		message = strings.TrimSuffix(fmt.Sprintln(mess...), "\n")
+	}
+
+	var line string
+	if l.Format == FormatJSON {
+		line = l.jsonLine(level, tag, message)
+	} else {
+		line = l.textLine(tag, tagColor, message)
+	}
+
+	l.sink.write(level, []byte(line))
+}
+
+func (l *Logger) textLine(tag string, tagColor []int, message string) string {
 	logTime := time.Now().Format("2006/01/02 15:04:05")
-	ansiRequired := runtime.GOOS != bad_os && !l.logIntoFile
+	ansiRequired := runtime.GOOS != bad_os && l.sink.ansi
 	loggerName := l.Name + " |  "
 
 	if ansiRequired {
@@ -96,6 +137,8 @@ func (l *Logger) log(tag string, tagColor []int, format bool, formatString strin
 		loggerName = l.ansi(2) + loggerName + ansi_clear
 	}
 
+	line := &strings.Builder{}
+
 	if len(tag) > 0 {
 		tagLog := "[" + tag + "]"
 
@@ -103,21 +146,51 @@ func (l *Logger) log(tag string, tagColor []int, format bool, formatString strin
 			tagLog = l.ansi(tagColor...) + tagLog + ansi_clear
 		}
 
-		l.writer(tagLog, " ")
+		line.WriteString(tagLog + " ")
 	}
 
-	l.writer(logTime, " ", loggerName)
+	line.WriteString(logTime + " " + loggerName + message)
 
-	if format {
-		l.writerf(formatString, mess...)
-		l.writerln()
-	} else {
-		l.writerln(mess...)
+	for _, field := range l.fields {
+		key := field.key + "="
+		if ansiRequired {
+			key = l.ansi(2) + key + ansi_clear
+		}
+		line.WriteString(" " + key + fmt.Sprint(field.value))
+	}
+
+	line.WriteString("\n")
+	return line.String()
+}
+
+func (l *Logger) jsonLine(level LogLevel, tag string, message string) string {
+	line := &strings.Builder{}
+
+	line.WriteString(`{"time":` + jsonValue(time.Now().Format(time.RFC3339Nano)))
+	if len(tag) > 0 {
+		line.WriteString(`,"level":` + jsonValue(level.String()))
 	}
+	line.WriteString(`,"logger":` + jsonValue(l.Name))
+	line.WriteString(`,"message":` + jsonValue(message))
 
-	if l.logIntoFile {
-		l.fileWriter.Flush()
+	for _, field := range l.fields {
+		line.WriteString("," + jsonValue(field.key) + ":" + jsonValue(field.value))
 	}
+
+	line.WriteString("}\n")
+	return line.String()
+}
+
+func jsonValue(value interface{}) string {
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(value))
+	}
+	return string(b)
 }
 
 func (l *Logger) ansi(codes ...int) string {
@@ -133,3 +206,82 @@ func (l *Logger) ansi(codes ...int) string {
 
 	return ansiSpaceCode
 }
+
+func (s *logSink) write(level LogLevel, line []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if levelWriter, ok := s.writer.(LevelWriter); ok {
+		levelWriter.WriteLevel(level, line)
+		return
+	}
+	s.writer.Write(line)
+}
+
+func (l LogLevel) String() string {
+	switch l {
+	case LevelNote:
+		return "note"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return strconv.Itoa(int(l))
+}
+
+// NewSyslogWriter connects to a syslog daemon. With an empty network the local
+// socket is used, which is what most Unix systems listen on.
+func NewSyslogWriter(network, addr, tag string) (*SyslogWriter, error) {
+	if network != "" {
+		conn, err := net.Dial(network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &SyslogWriter{Tag: tag, conn: conn}, nil
+	}
+
+	for _, socket := range strings.Split(syslog_sockets, ",") {
+		for _, socketNetwork := range []string{"unixgram", "unix"} {
+			if conn, err := net.Dial(socketNetwork, socket); err == nil {
+				return &SyslogWriter{Tag: tag, conn: conn}, nil
+			}
+		}
+	}
+
+	return nil, errors.New("could not connect to a local syslog socket")
+}
+
+func (s *SyslogWriter) Write(p []byte) (n int, err error) {
+	return s.WriteLevel(LevelInfo, p)
+}
+
+func (s *SyslogWriter) WriteLevel(level LogLevel, p []byte) (n int, err error) {
+	severity := 6
+	switch level {
+	case LevelNote:
+		severity = 5
+	case LevelWarn:
+		severity = 4
+	case LevelError:
+		severity = 3
+	}
+
+	message := fmt.Sprintf("<%d>%s %s[%d]: %s",
+		syslog_facility_user * 8 + severity, time.Now().Format(time.Stamp), s.Tag, os.Getpid(),
+		strings.TrimSuffix(string(p), "\n"))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err := s.conn.Write([]byte(message)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *SyslogWriter) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"strings"
+)
+
+// MethodOverride lets POST requests name their method in the X-HTTP-Method-Override
+// header or the _method form field. Only PUT, PATCH and DELETE can be asked for.
+// It has to run before routing, Server.MethodOverride installs it.
+func MethodOverride(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost {
+			method := req.Header.Get(header_method_override)
+			if method == "" && isForm(req) {
+				method = req.PostFormValue(method_override_field)
+			}
+
+			switch method = strings.ToUpper(method); method {
+			case http.MethodPut, http.MethodPatch, http.MethodDelete:
+				req = req.WithContext(req.Context())
+				req.Method = method
+			}
+		}
+
+		next.ServeHTTP(res, req)
+	})
+}
+
+// serveImplicit answers HEAD with the GET route of the path and OPTIONS with
+// the allowed methods when the path has no route of its own for them.
+func (r *Router) serveImplicit(res http.ResponseWriter, req *http.Request, methods []string) bool {
+	switch req.Method {
+	case http.MethodHead:
+		get := req.Clone(req.Context())
+		get.Method = http.MethodGet
+
+		match := &mux.RouteMatch{}
+		if !r.root().mux.Match(get, match) || match.MatchErr != nil {
+			return false
+		}
+
+		match.Handler.ServeHTTP(&headResponseWriter{res}, mux.SetURLVars(req, match.Vars))
+		return true
+	case http.MethodOptions:
+		res.Header().Set(header_allow, strings.Join(methods, ", "))
+		res.WriteHeader(http.StatusNoContent)
+		return true
+	}
+	return false
+}
+
+func (r *Router) root() *Router {
+	router := r
+	for router.parent != nil {
+		router = router.parent
+	}
+	return router
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func isForm(req *http.Request) bool {
+	contentType := mediaType(req.Header.Get(header_content_type))
+	return contentType == mime_form || contentType == mime_multipart_form
+}
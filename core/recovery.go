@@ -0,0 +1,72 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime/debug"
+)
+
+// OnPanic answers panics whose value has the same type as sample with resolver,
+// other panics get the 500 error of the router owning the route.
+func (s *Server) OnPanic(sample interface{}, resolver PanicResolver) {
+	if s.panicResolvers == nil {
+		s.panicResolvers = map[reflect.Type]PanicResolver{}
+	}
+	s.panicResolvers[reflect.TypeOf(sample)] = resolver
+}
+
+func (s *Server) recoveryHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		ctx := s.getContext(res, req, TrackTime())
+		// Every router the request passes through stores itself in ctx.router, so a
+		// panic is answered with the 500 of the router owning the route.
+		req = req.WithContext(context.WithValue(req.Context(), panic_router_key, &ctx.router))
+		ctx.Request = req
+
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			s.recover(ctx, recovered, debug.Stack())
+		}()
+
+		next.ServeHTTP(ctx.Response, req)
+	})
+}
+
+func (s *Server) recover(ctx *Context, recovered interface{}, stack []byte) {
+	MainLogger.Err("Recovered from panic in", ctx.Request.Method, ctx.Request.RequestURI+":", recovered, "\n"+string(stack))
+
+	if ctx.HeadersSent() {
+		return
+	}
+
+	if resolver, ok := s.panicResolvers[reflect.TypeOf(recovered)]; ok {
+		if response := resolver(ctx, recovered); response != (Response{}) {
+			ctx.Render(response.Status, response)
+		}
+		return
+	}
+
+	internalErr := ctx.router.internalError()
+	if s.Debug {
+		internalErr = internalErr.WithDetail(fmt.Sprint(recovered, "\n", string(stack)))
+	}
+
+	ctx.Render(internalErr.Status, NewErrorResponse(internalErr))
+}
+
+func (r *Router) internalError() *Error {
+	internalErr := r.Errors.Get(http.StatusInternalServerError)
+	if internalErr.Response == nil {
+		return NewError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+	return internalErr
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+func wrapResponseWriter(res http.ResponseWriter) *responseWriter {
+	if writer, ok := res.(*responseWriter); ok {
+		return writer
+	}
+	return &responseWriter{ResponseWriter: res}
+}
+
+// StatusCode is the status written so far, 200 once a body was written without
+// an explicit status and 0 while nothing has been written.
+func (c *Context) StatusCode() int {
+	return c.writer.status
+}
+
+func (c *Context) BytesWritten() int64 {
+	return c.writer.bytes
+}
+
+func (c *Context) HeadersSent() bool {
+	return c.writer.status != 0
+}
+
+// FirstWrite is when the headers were sent, zero while they were not.
+func (c *Context) FirstWrite() time.Time {
+	return c.writer.firstWrite
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+
+	w.status = status
+	w.firstWrite = time.Now()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	w.FlushError()
+}
+
+// FlushError is used by http.ResponseController, it fails with http.ErrNotSupported
+// when none of the writers below w can flush.
+func (w *responseWriter) FlushError() error {
+	if !canFlush(w.ResponseWriter) {
+		return http.ErrNotSupported
+	}
+
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+
+	conn, buffer, err := hijacker.Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+		w.firstWrite = time.Now()
+	}
+	return conn, buffer, err
+}
+
+func (w *responseWriter) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := w.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// canFlush looks through res and the writers it wraps for one that can flush.
+// responseWriter always has a Flush method, so it only counts its own writer.
+func canFlush(res http.ResponseWriter) bool {
+	for {
+		switch writer := res.(type) {
+		case *responseWriter:
+			res = writer.ResponseWriter
+		case http.Flusher, interface{ FlushError() error }:
+			return true
+		case interface{ Unwrap() http.ResponseWriter }:
+			res = writer.Unwrap()
+		default:
+			return false
+		}
+	}
+}
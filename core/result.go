@@ -0,0 +1,105 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Result adapts a resolver that returns its message and an error. The message is
+// sent as a success response, the error is translated through the router's ErrorMap.
+func Result(resolver ResultResolver) Resolver {
+	return func(ctx *Context) Response {
+		message, err := resolver(ctx)
+		if err != nil {
+			return ctx.Fail(err)
+		}
+
+		if response, ok := message.(Response); ok {
+			return response
+		}
+		return NewSuccessResponse(message)
+	}
+}
+
+// MapError makes errors matching target with errors.Is answer with the error
+// registered under code. Routers mounted under this one inherit the mapping.
+func (r *Router) MapError(target error, code int) {
+	r.errorCodes = append(r.errorCodes, errorCode{target, code})
+}
+
+// Fail translates err into a response. Errors created with Code or Err and errors
+// registered with MapError are looked up in the router's ErrorMap by their code,
+// any other *Error is sent as is and everything else is a 500.
+func (c *Context) Fail(err error) Response {
+	translated := c.router.translateError(err)
+	if translated.code != 0 {
+		translated = c.localize(translated, translated.code)
+	}
+
+	if !c.router.problemDetails() {
+		return NewErrorResponse(translated)
+	}
+
+	c.Header(header_content_type, mime_problem_json)
+	c.Status(translated.Status)
+	json.NewEncoder(c.Response).Encode(Problem{
+		Type: problem_default_type,
+		Title: http.StatusText(translated.Status),
+		Status: translated.Status,
+		Detail: translated.Message,
+		Instance: c.Request.URL.Path,
+	})
+	return NullResponse()
+}
+
+func (r *Router) translateError(err error) *Error {
+	if mapped, ok := r.mapError(err); ok {
+		return mapped
+	}
+
+	RouterLogger.Err("Unhandled error:", err)
+	return r.internalError()
+}
+
+// mapError finds the *Error for err without falling back to the 500.
+func (r *Router) mapError(err error) (*Error, bool) {
+	var bindErr BindError
+	if errors.As(err, &bindErr) && bindErr.Code != 0 {
+		if mapped, ok := r.Errors.lookup(bindErr.Code); ok {
+			return mapped, true
+		}
+	}
+
+	var coreErr *Error
+	if errors.As(err, &coreErr) {
+		if coreErr.code != 0 {
+			if mapped, ok := r.Errors.lookup(coreErr.code); ok {
+				return mapped, true
+			}
+		}
+		if coreErr.Response != nil {
+			return coreErr, true
+		}
+	}
+
+	for router := r; router != nil; router = router.parent {
+		for _, n := range router.errorCodes {
+			if errors.Is(err, n.target) {
+				if mapped := r.Errors.Get(n.code); mapped.Response != nil {
+					return mapped, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+func (r *Router) problemDetails() bool {
+	for router := r; router != nil; router = router.parent {
+		if router.ProblemDetails {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,288 @@
+package core
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+func NewRotatingFile(path string) *RotatingFile {
+	return &RotatingFile{
+		Path: path,
+		Daily: true,
+	}
+}
+
+func (f *RotatingFile) Open() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file != nil {
+		return nil
+	}
+	return f.open()
+}
+
+func (f *RotatingFile) Write(p []byte) (n int, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	dayChanged := f.Daily && time.Now().Format(log_date_format) != f.day
+	sizeExceeded := f.MaxSize > 0 && f.size > 0 && f.size + int64(len(p)) > f.MaxSize
+
+	if dayChanged || sizeExceeded {
+		if err := f.rotate(dayChanged); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err = f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Reopen closes the current file and opens it again by name,
+// so a file moved away by an external logrotate is recreated.
+func (f *RotatingFile) Reopen() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file != nil {
+		f.file.Close()
+		f.file = nil
+	}
+	return f.open()
+}
+
+// ReopenOnSignal reopens the file every time the process receives one of the signals,
+// SIGHUP by default.
+func (f *RotatingFile) ReopenOnSignal(signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	f.signalOnce.Do(func() {
+		reopen := make(chan os.Signal, 1)
+		signal.Notify(reopen, signals...)
+
+		go func() {
+			for range reopen {
+				if err := f.Reopen(); err != nil {
+					MainLogger.Err("Could not reopen log file", f.Path, err)
+				}
+			}
+		}()
+	})
+}
+
+// Close closes the file once the backups rotated so far are compressed and pruned.
+func (f *RotatingFile) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.pending.Wait()
+
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) activePath(day string) string {
+	return f.Path + "-" + day + log_file_extension
+}
+
+func (f *RotatingFile) open() error {
+	f.day = time.Now().Format(log_date_format)
+
+	file, err := os.OpenFile(f.activePath(f.day), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+func (f *RotatingFile) rotate(dayChanged bool) error {
+	f.file.Close()
+	f.file = nil
+
+	backup := f.activePath(f.day)
+	if !dayChanged {
+		backup = f.nextBackupPath()
+		if err := os.Rename(f.activePath(f.day), backup); err != nil {
+			return err
+		}
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.workerOnce.Do(func() {
+		f.cleanups = make(chan rotatedFile, log_cleanup_queue_size)
+		go f.cleanupWorker()
+	})
+	f.pending.Add(1)
+	f.cleanups <- rotatedFile{backup, f.activePath(f.day)}
+	return nil
+}
+
+func (f *RotatingFile) nextBackupPath() string {
+	if f.backupDay != f.day {
+		f.backupDay, f.backupNumber = f.day, 0
+		for _, backup := range f.backups() {
+			if backup.day == f.day && backup.number > f.backupNumber {
+				f.backupNumber = backup.number
+			}
+		}
+	}
+
+	f.backupNumber++
+	return f.Path + "-" + f.day + "." + strconv.Itoa(f.backupNumber) + log_file_extension
+}
+
+func (f *RotatingFile) cleanupWorker() {
+	for rotated := range f.cleanups {
+		f.cleanup(rotated.backup, rotated.active)
+		f.pending.Done()
+	}
+}
+
+func (f *RotatingFile) cleanup(backup string, active string) {
+	if f.Compress {
+		// A backup can be pruned by an earlier cleanup before its turn comes.
+		if err := compressFile(backup); err != nil && !os.IsNotExist(err) {
+			MainLogger.Err("Could not compress log file", backup, err)
+		}
+	}
+
+	if f.MaxBackups <= 0 && f.MaxAge <= 0 {
+		return
+	}
+
+	backups := []backupFile{}
+	for _, backup := range f.backups() {
+		if backup.path != active {
+			backups = append(backups, backup)
+		}
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].day != backups[j].day {
+			return backups[i].day > backups[j].day
+		}
+		if backups[i].number == 0 || backups[j].number == 0 {
+			return backups[i].number == 0 && backups[j].number != 0
+		}
+		return backups[i].number > backups[j].number
+	})
+
+	for i, backup := range backups {
+		tooMany := f.MaxBackups > 0 && i >= f.MaxBackups
+		tooOld := f.MaxAge > 0 && time.Since(backup.modTime) > f.MaxAge
+
+		if tooMany || tooOld {
+			os.Remove(backup.path)
+		}
+	}
+}
+
+// backups are the <Path>-<date>.log and <Path>-<date>.<n>.log files of this logger,
+// compressed or not. The glob also finds loggers whose Path starts with this one,
+// like app-worker next to app, their files are left out.
+func (f *RotatingFile) backups() []backupFile {
+	matches, err := filepath.Glob(f.Path + "-*" + log_file_extension + "*")
+	if err != nil {
+		return nil
+	}
+
+	backups := []backupFile{}
+	for _, path := range matches {
+		backup, ok := f.parseBackup(path)
+		if !ok {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			backup.modTime = info.ModTime()
+			backups = append(backups, backup)
+		}
+	}
+	return backups
+}
+
+func (f *RotatingFile) parseBackup(path string) (backupFile, bool) {
+	name := strings.TrimPrefix(path, f.Path + "-")
+	name = strings.TrimSuffix(name, log_compressed_extension)
+	if !strings.HasSuffix(name, log_file_extension) {
+		return backupFile{}, false
+	}
+
+	day, n, numbered := strings.Cut(strings.TrimSuffix(name, log_file_extension), ".")
+	if _, err := time.Parse(log_date_format, day); err != nil {
+		return backupFile{}, false
+	}
+
+	backup := backupFile{path: path, day: day}
+	if numbered {
+		number, err := strconv.Atoi(n)
+		if err != nil || number < 1 {
+			return backupFile{}, false
+		}
+		backup.number = number
+	}
+	return backup, true
+}
+
+func compressFile(path string) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := os.OpenFile(path + log_compressed_extension, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	writer := gzip.NewWriter(target)
+	if _, err := io.Copy(writer, source); err != nil {
+		writer.Close()
+		target.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		target.Close()
+		return err
+	}
+	if err := target.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
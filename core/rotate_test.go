@@ -0,0 +1,64 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotationRetention(t *testing.T) {
+	sink := MainLogger.sink
+	defer func() { MainLogger.sink = sink }()
+	logs := &bytes.Buffer{}
+	MainLogger.SetOutput(logs)
+
+	dir := t.TempDir()
+	day := time.Now().Format(log_date_format)
+	other := filepath.Join(dir, "app-worker-" + day + log_file_extension)
+	os.WriteFile(other, []byte("kept\n"), 0644)
+
+	f := NewRotatingFile(filepath.Join(dir, "app"))
+	f.MaxSize = 50
+	f.MaxBackups = 2
+	f.Compress = true
+
+	for i := 0; i < 40; i++ {
+		if _, err := f.Write([]byte(strings.Repeat("x", 30) + "\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if logs.Len() > 0 {
+		t.Errorf("rotation logged %s", logs.String())
+	}
+
+	entries, _ := os.ReadDir(dir)
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	prefix := "app-" + day
+	want := []string{
+		prefix + ".38" + log_file_extension + log_compressed_extension,
+		prefix + ".39" + log_file_extension + log_compressed_extension,
+		prefix + log_file_extension,
+		"app-worker-" + day + log_file_extension,
+	}
+	if strings.Join(names, " ") != strings.Join(want, " ") {
+		t.Fatalf("got files %v, want %v", names, want)
+	}
+
+	// Numbers keep growing after retention removed the lower ones.
+	if next := f.nextBackupPath(); !strings.HasSuffix(next, ".40" + log_file_extension) {
+		t.Fatalf("next backup is %s", next)
+	}
+}
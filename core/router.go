@@ -1,59 +1,82 @@
 package core
 
 import (
-	"encoding/json"
+	"fmt"
 	"github.com/gorilla/mux"
 	"net/http"
-	"reflect"
 	"time"
 )
 
-func NewRouter(init func(r *Router)) interface{} {
-	return &struct {
-		*Router
-		Init func(r *Router)
-	}{Init: init}
+func NewRouter(init func(r *Router)) *InitRouter {
+	return &InitRouter{Init: init}
 }
 
 func NewCoreRouter() *Router {
-	return &Router{
+	router := &Router{
 		mux: mux.NewRouter(),
 		Errors: NewErrorMap(),
+		webSockets: &webSocketSet{contexts: map[*WebSocketContext]bool{}},
 	}
+	router.setFallbacks()
+	return router
 }
 
 func (r *Router) GetHandler() *mux.Router {
 	return r.mux
 }
 
-func (r *Router) Get(path string, resolver Resolver) {
-	r.Handle(path, resolver, http.MethodGet)
+func (r *Router) Get(path string, resolver Resolver) *Route {
+	return r.Handle(path, resolver, http.MethodGet)
 }
 
-func (r *Router) Put(path string, resolver Resolver) {
-	r.Handle(path, resolver, http.MethodPut)
+func (r *Router) Put(path string, resolver Resolver) *Route {
+	return r.Handle(path, resolver, http.MethodPut)
 }
 
-func (r *Router) Post(path string, resolver Resolver) {
-	r.Handle(path, resolver, http.MethodPost)
+func (r *Router) Post(path string, resolver Resolver) *Route {
+	return r.Handle(path, resolver, http.MethodPost)
 }
 
-func (r *Router) Delete(path string, resolver Resolver) {
-	r.Handle(path, resolver, http.MethodDelete)
+func (r *Router) Delete(path string, resolver Resolver) *Route {
+	return r.Handle(path, resolver, http.MethodDelete)
 }
 
-func (r *Router) All(path string, resolver Resolver) {
-	r.Handle(path, resolver)
+func (r *Router) Patch(path string, resolver Resolver) *Route {
+	return r.Handle(path, resolver, http.MethodPatch)
 }
 
-func (r *Router) Handle(path string, resolver Resolver, methods ...string) {
+// Head routes HEAD requests. Without one HEAD is answered by the GET route of the path.
+func (r *Router) Head(path string, resolver Resolver) *Route {
+	return r.Handle(path, resolver, http.MethodHead)
+}
+
+// Options routes OPTIONS requests. Without one OPTIONS is answered with 204 and Allow.
+func (r *Router) Options(path string, resolver Resolver) *Route {
+	return r.Handle(path, resolver, http.MethodOptions)
+}
+
+func (r *Router) Connect(path string, resolver Resolver) *Route {
+	return r.Handle(path, resolver, http.MethodConnect)
+}
+
+func (r *Router) Trace(path string, resolver Resolver) *Route {
+	return r.Handle(path, resolver, http.MethodTrace)
+}
+
+func (r *Router) All(path string, resolver Resolver) *Route {
+	return r.Handle(path, resolver)
+}
+
+func (r *Router) Handle(path string, resolver Resolver, methods ...string) *Route {
 	handlerFunc := r.mux.HandleFunc(path, r.resolver(resolver))
 	if len(methods) > 0 {
 		handlerFunc.Methods(methods...)
 	}
+	return r.track(handlerFunc, resolver)
 }
 
 func (r *Router) Use(middlewareResolver MiddlewareResolver) {
+	r.middleware = append(r.middleware, funcName(middlewareResolver))
 	r.mux.Use(func(handler http.Handler) http.Handler {
 		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
 			profiler := TrackTime()
@@ -62,57 +85,49 @@ func (r *Router) Use(middlewareResolver MiddlewareResolver) {
 	})
 }
 
-func (r *Router) Set(path string, router interface{}) {
-	routerPrefix := r.mux.PathPrefix(path).Subrouter()
-	newPrefixedRouter := &Router{routerPrefix, NewErrorMap()}
-
-	routerValue := reflect.ValueOf(router).Elem()
-	field := routerValue.FieldByName("Router")
-
-	if field.IsValid() {
-		field.Set(reflect.ValueOf(newPrefixedRouter))
-	} else {
-		return
+// Set mounts router at path. router is a *InitRouter from NewRouter or a pointer to a
+// struct embedding *Router with an Init method or field, which is called once it is mounted.
+func (r *Router) Set(path string, router interface{}) error {
+	init, err := routerInit(router)
+	if err != nil {
+		err = fmt.Errorf("could not set router at %s: %w", path, err)
+		RouterLogger.Err(err)
+		return err
 	}
 
-	routerType := reflect.TypeOf(router)
-	method, ok := routerType.MethodByName(router_init_func_name)
-	if !ok {
-		simpleMethod := routerValue.FieldByName(router_init_func_name)
-
-		if simpleMethod != reflect.Zero(reflect.TypeOf(simpleMethod)).Interface() {
-			simpleMethod.Call([]reflect.Value{reflect.ValueOf(newPrefixedRouter)})
-		} else {
-			RouterLogger.Warn("Missing Init method in router!")
-		}
-		return
-	}
-
-	method.Func.Call([]reflect.Value{reflect.ValueOf(router)})
+	init(r.mount(path, GroupConfig{}))
+	return nil
 }
 
-func (r *Router) SetHandler(path string, handler http.Handler) {
-	r.mux.Handle(path, handler)
+func (r *Router) SetHandler(path string, handler http.Handler) *Route {
+	return r.track(r.mux.Handle(path, handler), handler)
 }
 
 func (r *Router) resolver(resolver Resolver) func(res http.ResponseWriter, req *http.Request) {
 	return func(res http.ResponseWriter, req *http.Request) {
 		profiler := TrackTime()
 
-		response := resolver(r.getContext(res, req, profiler))
+		ctx := r.getContext(res, req, profiler)
+		response := resolver(ctx)
 
 		if response != (Response{}) {
-			res.WriteHeader(response.Status)
-			json.NewEncoder(res).Encode(response)
+			ctx.Render(response.Status, response)
 		}
 	}
 }
 
 func (r *Router) getContext(res http.ResponseWriter, req *http.Request, executionTracker func() time.Duration) *Context {
+	if owner, ok := req.Context().Value(panic_router_key).(**Router); ok {
+		*owner = r
+	}
+
+	writer := wrapResponseWriter(res)
 	return &Context{
 		Request: req,
-		Response: res,
+		Response: writer,
 		TrackTime: executionTracker,
+		router: r,
+		writer: writer,
 	}
 }
 
@@ -0,0 +1,180 @@
+package core
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Routes lists the routes of the router and the routers mounted under it in the
+// order they are matched.
+func (r *Router) Routes() []RouteInfo {
+	routes := []RouteInfo{}
+	for _, n := range r.walkRoutes() {
+		if n.route.GetHandler() != nil {
+			routes = append(routes, n.info())
+		}
+	}
+	return routes
+}
+
+func (r *Router) track(route *mux.Route, handler interface{}) *Route {
+	tracked := &Route{route: route, router: r, handler: funcName(handler)}
+	root := r.root()
+	root.routes = append(root.routes, tracked)
+	return tracked
+}
+
+func (r *Router) walkRoutes() []walkedRoute {
+	tracked := map[*mux.Route]*Route{}
+	for _, n := range r.root().routes {
+		tracked[n.route] = n
+	}
+
+	walked := []walkedRoute{}
+	r.mux.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		walked = append(walked, walkedRoute{
+			route: route,
+			tracked: tracked[route],
+			ancestors: append([]*mux.Route{}, ancestors...),
+		})
+		return nil
+	})
+	return walked
+}
+
+// checkRoutes warns about routes registered twice and static routes that an
+// earlier route or mounted router answers instead.
+func (r *Router) checkRoutes() {
+	walked := r.walkRoutes()
+	for i, later := range walked {
+		if later.route.GetHandler() == nil {
+			continue
+		}
+
+		for _, earlier := range walked[:i] {
+			if later.descendsFrom(earlier.route) {
+				continue
+			}
+			if earlier.route.GetHandler() != nil && later.duplicates(earlier) {
+				RouterLogger.Warn("Route", later.describe(), "is registered twice")
+				break
+			}
+			if later.shadowedBy(earlier) {
+				RouterLogger.Warn("Route", later.describe(), "is shadowed by", earlier.describe())
+				break
+			}
+		}
+	}
+}
+
+func (w walkedRoute) info() RouteInfo {
+	info := RouteInfo{
+		Methods: w.methods(),
+		Name: w.route.GetName(),
+		Middleware: []string{},
+	}
+	info.Path, _ = w.route.GetPathTemplate()
+	info.Host, _ = w.route.GetHostTemplate()
+
+	if w.tracked == nil {
+		info.Handler = funcName(w.route.GetHandler())
+		return info
+	}
+
+	info.Handler = w.tracked.handler
+	for router := w.tracked.router; router != nil; router = router.parent {
+		info.Middleware = append(append([]string{}, router.middleware...), info.Middleware...)
+	}
+	return info
+}
+
+func (w walkedRoute) methods() []string {
+	methods, err := w.route.GetMethods()
+	if err != nil {
+		return []string{}
+	}
+	return methods
+}
+
+func (w walkedRoute) describe() string {
+	path, _ := w.route.GetPathTemplate()
+	if methods := w.methods(); len(methods) > 0 {
+		return strings.Join(methods, ",") + " " + path
+	}
+	return path
+}
+
+func (w walkedRoute) descendsFrom(route *mux.Route) bool {
+	for _, n := range w.ancestors {
+		if n == route {
+			return true
+		}
+	}
+	return false
+}
+
+func (w walkedRoute) duplicates(earlier walkedRoute) bool {
+	path, _ := w.route.GetPathTemplate()
+	earlierPath, _ := earlier.route.GetPathTemplate()
+	host, _ := w.route.GetHostTemplate()
+	earlierHost, _ := earlier.route.GetHostTemplate()
+	if path != earlierPath || host != earlierHost {
+		return false
+	}
+
+	methods, earlierMethods := w.methods(), earlier.methods()
+	if len(methods) == 0 || len(earlierMethods) == 0 {
+		return true
+	}
+	for _, method := range methods {
+		for _, earlierMethod := range earlierMethods {
+			if method == earlierMethod {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// shadowedBy only looks at routes without variables or hosts, whose requests can be built.
+func (w walkedRoute) shadowedBy(earlier walkedRoute) bool {
+	path, err := w.route.GetPathTemplate()
+	if err != nil || strings.Contains(path, "{") {
+		return false
+	}
+	if host, _ := w.route.GetHostTemplate(); host != "" {
+		return false
+	}
+
+	methods := w.methods()
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet}
+	}
+	for _, method := range methods {
+		req, err := http.NewRequest(method, path, nil)
+		if err != nil {
+			return false
+		}
+		if earlier.route.Match(req, &mux.RouteMatch{}) {
+			return true
+		}
+	}
+	return false
+}
+
+func funcName(handler interface{}) string {
+	value := reflect.ValueOf(handler)
+	if !value.IsValid() {
+		return ""
+	}
+	if value.Kind() != reflect.Func {
+		return reflect.TypeOf(handler).String()
+	}
+
+	name := runtime.FuncForPC(value.Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+	return name[strings.LastIndex(name, "/") + 1:]
+}
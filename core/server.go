@@ -3,7 +3,8 @@ package core
 import (
 	"context"
 	"fmt"
-	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -17,6 +18,7 @@ func NewServer(addr string) *Server {
 		Addr: addr,
 		Debug: false,
 		GracefulShutdown: false,
+		Recovery: true,
 		NativeServer: &http.Server{
 			Addr: addr,
 		},
@@ -28,11 +30,12 @@ func NewServer(addr string) *Server {
 				map[string]string{},
 			},
 		},
+		Hub: NewHub(),
 		onError: func(err error) {
 			MainLogger.Err(err)
 		},
 		onStart: func(addr string) {
-			fmt.Println(winter_logo)
+			fmt.Print(winter_logo + "\n")
 			MainLogger.Info("Your server is running on " + addr)
 		},
 		onShutdown: func(err error) {
@@ -85,25 +88,62 @@ func (s *Server) gracefulShutdown(useTLS bool, certPath, keyPath string) {
 
 	<-stop
 
-	ctx, _ := context.WithTimeout(context.Background(), 5 * time.Second)
+	// Hijacked WebSocket connections are not tracked by NativeServer.Shutdown,
+	// so peers are told to go away before the listener is closed. Hub clients go
+	// first to leave their rooms, then every other connection.
+	s.Hub.Shutdown(websocket.CloseGoingAway, hub_shutdown_reason)
+	s.webSockets.closeAll(websocket.CloseGoingAway, hub_shutdown_reason)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5 * time.Second)
+	defer cancel()
 
 	s.onShutdown(s.NativeServer.Shutdown(ctx))
 }
 
 func (s *Server) start(useTLS bool, certPath, keyPath string) {
-	s.onStart(s.Addr)
+	listener, err := s.listen(useTLS)
+	if err != nil {
+		s.onError(err)
+		return
+	}
+
+	addr := s.Addr
+	if os.Getenv(ListenFDEnv) != "" {
+		addr = listener.Addr().String()
+	}
+	s.onStart(addr)
+
 	if useTLS {
-		if err := s.NativeServer.ListenAndServeTLS(certPath, keyPath); err != nil {
-			s.onError(err)
-		}
+		err = s.NativeServer.ServeTLS(listener, certPath, keyPath)
 	} else {
-		if err := s.NativeServer.ListenAndServe(); err != nil {
-			s.onError(err)
+		err = s.NativeServer.Serve(listener)
+	}
+	if err != nil && err != http.ErrServerClosed {
+		s.onError(err)
+	}
+}
+
+// listen takes over the socket passed in WINTER_LISTEN_FD, as winter run does on
+// every restart, and listens on the server's address otherwise.
+func (s *Server) listen(useTLS bool) (net.Listener, error) {
+	if fd := os.Getenv(ListenFDEnv); fd != "" {
+		n, err := strconv.Atoi(fd)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", ListenFDEnv, fd, err)
 		}
+		return net.FileListener(os.NewFile(uintptr(n), ListenFDEnv))
+	}
+
+	addr := s.NativeServer.Addr
+	if addr == "" && useTLS {
+		addr = ":https"
+	} else if addr == "" {
+		addr = ":http"
 	}
+	return net.Listen("tcp", addr)
 }
 
-func (s *Server) processRouterByDefault() *mux.Router {
+func (s *Server) processRouterByDefault() http.Handler {
 	if len(s.CORS.headerMap) > 0 {
 		s.Use(s.corsMiddleware)
 	}
@@ -112,14 +152,31 @@ func (s *Server) processRouterByDefault() *mux.Router {
 	}
 	if s.Debug {
 		s.Use(s.loggingMiddleware)
+		s.Get(debug_routes_path, s.routesResolver)
+	}
+	s.checkRoutes()
+
+	var handler http.Handler = s.GetHandler()
+	if s.MethodOverride {
+		handler = MethodOverride(handler)
 	}
+	if s.Recovery {
+		handler = s.recoveryHandler(handler)
+	}
+	if s.grpcServer != nil {
+		handler = s.grpcHandler(handler)
+	}
+
+	return s.configureHTTP2(handler)
+}
 
-	return s.GetHandler()
+func (s *Server) routesResolver(ctx *Context) Response {
+	return NewSuccessResponse(s.Routes())
 }
 
 func (s *Server) loggingMiddleware(ctx *MiddlewareContext) {
 	ctx.Next()
-	RequestLogger.Info(ctx.Request.Method, ctx.Request.RequestURI,
+	RequestLogger.Info(ctx.Request.Method, ctx.Request.RequestURI, ctx.StatusCode(),
 		"ms -", float32(ctx.TrackTime().Nanoseconds()) / float32(1000000))
 }
 
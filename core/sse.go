@@ -0,0 +1,150 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSE starts an event stream. It fails with ErrStreamingUnsupported before anything
+// is written when no writer below c.Response can flush.
+func (c *Context) SSE() (*EventStream, error) {
+	if !canFlush(c.Response) {
+		return nil, ErrStreamingUnsupported
+	}
+	controller := http.NewResponseController(c.Response)
+
+	c.Header("Content-Type", sse_content_type)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	if err := controller.Flush(); err != nil {
+		return nil, err
+	}
+
+	return &EventStream{
+		ctx: c,
+		controller: controller,
+		lastEventID: c.Request.Header.Get(sse_last_event_id),
+	}, nil
+}
+
+// LastEventID is the id of the last event the client received before reconnecting,
+// empty on the first connection.
+func (e *EventStream) LastEventID() string {
+	return e.lastEventID
+}
+
+func (e *EventStream) Done() <-chan struct{} {
+	return e.ctx.Request.Context().Done()
+}
+
+// ID sets the id sent along with the next event. Line breaks are removed, they would
+// end the field and let the rest of id be read as another field.
+func (e *EventStream) ID(id string) {
+	e.mutex.Lock()
+	e.id = stripLineBreaks(id)
+	e.mutex.Unlock()
+}
+
+func (e *EventStream) Event(name string, data interface{}) error {
+	payload, err := e.encode(data)
+	if err != nil {
+		return err
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	frame := &strings.Builder{}
+	if e.id != "" {
+		fmt.Fprintf(frame, "id: %s\n", e.id)
+		e.id = ""
+	}
+	if name = stripLineBreaks(name); name != "" {
+		fmt.Fprintf(frame, "event: %s\n", name)
+	}
+	for _, line := range splitLines(payload) {
+		fmt.Fprintf(frame, "data: %s\n", line)
+	}
+	frame.WriteString("\n")
+
+	return e.write(frame.String())
+}
+
+func (e *EventStream) Data(data interface{}) error {
+	return e.Event("", data)
+}
+
+func (e *EventStream) Retry(retry time.Duration) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.write("retry: " + strconv.FormatInt(retry.Milliseconds(), 10) + "\n\n")
+}
+
+func (e *EventStream) Comment(text string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	frame := &strings.Builder{}
+	for _, line := range splitLines(text) {
+		fmt.Fprintf(frame, ":%s\n", line)
+	}
+	frame.WriteString("\n")
+
+	return e.write(frame.String())
+}
+
+// Heartbeat keeps idle proxies from closing the stream by sending
+// an empty comment every interval until the client goes away.
+func (e *EventStream) Heartbeat(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := e.Comment(""); err != nil {
+					return
+				}
+			case <-e.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (e *EventStream) encode(data interface{}) (string, error) {
+	buffer := &bytes.Buffer{}
+	if err := json.NewEncoder(buffer).Encode(data); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+func (e *EventStream) write(frame string) error {
+	if err := e.ctx.Request.Context().Err(); err != nil {
+		return err
+	}
+
+	if _, err := e.ctx.Response.Write([]byte(frame)); err != nil {
+		return err
+	}
+	return e.controller.Flush()
+}
+
+// splitLines splits at every line break SSE knows, CRLF, CR and LF.
+func splitLines(text string) []string {
+	return strings.Split(strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n"), "\n")
+}
+
+func stripLineBreaks(value string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(value, "\r", ""), "\n", "")
+}
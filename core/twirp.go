@@ -0,0 +1,151 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Twirp serves a method of a service generated by winter generate rpc. Requests are
+// read as protobuf or JSON by their Content-Type and answered the same way. Errors are
+// translated through the router's ErrorMap like in Result and sent as Twirp errors
+// with the code matching their status, a returned *TwirpError is sent as is.
+func Twirp[T any, P interface{ *T; proto.Message }](method func(ctx *Context, req P) (proto.Message, error)) Resolver {
+	return func(ctx *Context) Response {
+		contentType := mediaType(ctx.Request.Header.Get(header_content_type))
+		if contentType != mime_protobuf && contentType != mime_json {
+			return ctx.sendTwirpError(&TwirpError{Code: twirp_bad_route, Msg: "unsupported Content-Type " + contentType})
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			return ctx.sendTwirpError(&TwirpError{Code: twirp_malformed, Msg: "could not read request: " + err.Error()})
+		}
+
+		req := P(new(T))
+		if contentType == mime_protobuf {
+			err = proto.Unmarshal(body, req)
+		} else {
+			err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, req)
+		}
+		if err != nil {
+			return ctx.sendTwirpError(&TwirpError{Code: twirp_malformed, Msg: "could not decode request: " + err.Error()})
+		}
+
+		res, err := method(ctx, req)
+		if err != nil {
+			return ctx.sendTwirpError(ctx.twirpError(err))
+		}
+
+		var out []byte
+		if contentType == mime_protobuf {
+			out, err = proto.Marshal(res)
+		} else {
+			out, err = protojson.Marshal(res)
+		}
+		if err != nil {
+			return ctx.sendTwirpError(ctx.twirpError(err))
+		}
+
+		ctx.Header(header_content_type, contentType)
+		ctx.Status(http.StatusOK)
+		ctx.Response.Write(out)
+		return NullResponse()
+	}
+}
+
+// TwirpBadRoute answers requests to unknown methods of a Twirp service, and requests
+// sent with another HTTP method than POST, with a bad_route error. Generated routers
+// use it as their NotFound and MethodNotAllowed resolver.
+func TwirpBadRoute(ctx *Context) Response {
+	message := "no handler for path " + strconv.Quote(ctx.Request.URL.Path)
+	if ctx.Request.Method != http.MethodPost {
+		message = "unsupported method " + strconv.Quote(ctx.Request.Method) + " (only POST is allowed)"
+	}
+	return ctx.sendTwirpError(&TwirpError{Code: twirp_bad_route, Msg: message})
+}
+
+// CallTwirp posts req as protobuf to url and decodes the answer into res. Twirp errors
+// are returned as *TwirpError. Generated clients call it for every method.
+func CallTwirp(ctx context.Context, client *http.Client, url string, req, res proto.Message) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set(header_content_type, mime_protobuf)
+
+	httpRes, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpRes.Body.Close()
+
+	data, err := io.ReadAll(httpRes.Body)
+	if err != nil {
+		return err
+	}
+
+	if httpRes.StatusCode != http.StatusOK {
+		twirpErr := &TwirpError{}
+		if json.Unmarshal(data, twirpErr) != nil || twirpErr.Code == "" {
+			return &TwirpError{Code: twirpCode(httpRes.StatusCode), Msg: "unexpected response " + httpRes.Status}
+		}
+		return twirpErr
+	}
+	return proto.Unmarshal(data, res)
+}
+
+func (e *TwirpError) Error() string {
+	return "twirp error " + e.Code + ": " + e.Msg
+}
+
+func (c *Context) twirpError(err error) *TwirpError {
+	var twirpErr *TwirpError
+	if errors.As(err, &twirpErr) {
+		return twirpErr
+	}
+
+	translated := c.router.translateError(err)
+	if translated.code != 0 {
+		translated = c.localize(translated, translated.code)
+	}
+	return &TwirpError{Code: twirpCode(translated.Status), Msg: fmt.Sprint(translated.Message)}
+}
+
+func (c *Context) sendTwirpError(err *TwirpError) Response {
+	status, ok := twirpStatuses[err.Code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	c.Header(header_content_type, mime_json)
+	c.Status(status)
+	json.NewEncoder(c.Response).Encode(err)
+	return NullResponse()
+}
+
+func twirpCode(status int) string {
+	if code, ok := twirpCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return twirp_internal
+	}
+	return twirp_unknown
+}
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"fmt"
+)
+
+// Name names the route for URL. Inside a group the name gets the group's name prefix.
+func (r *Route) Name(name string) *Route {
+	name = r.router.namePrefix + name
+	if r.router.mux.Get(name) != nil {
+		RouterLogger.Warn("Route name " + name + " is already taken, the last route named so wins")
+	}
+	if err := r.route.Name(name).GetError(); err != nil {
+		RouterLogger.Err("Could not name route " + name + ":", err)
+	}
+	return r
+}
+
+// URL builds the full path of the route named name, with the prefixes of the routers
+// it is mounted under. params are pairs of variable names and values.
+func (r *Router) URL(name string, params ...string) (string, error) {
+	route := r.mux.Get(name)
+	if route == nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownRoute, name)
+	}
+
+	url, err := route.URL(params...)
+	if err != nil {
+		return "", fmt.Errorf("could not build url of route %s: %w", name, err)
+	}
+	return url.String(), nil
+}
+
+func (c *Context) URL(name string, params ...string) (string, error) {
+	return c.router.URL(name, params...)
+}
@@ -1,6 +1,9 @@
 package core
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
 	"time"
 )
 
@@ -24,3 +27,11 @@ func Sender(json interface{}) Resolver {
 		return NullResponse()
 	}
 }
+
+func RandomID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
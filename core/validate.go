@@ -0,0 +1,287 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	builtinValidators = map[string]ValidatorFunc{
+		"required": validateRequired,
+		"min": validateMin,
+		"max": validateMax,
+		"len": validateLen,
+		"regex": validateRegex,
+		"enum": validateEnum,
+		"email": validateEmail,
+	}
+
+	regexCache = sync.Map{}
+)
+
+// Validate checks v against its `validate` tags using only the built-in rules.
+func Validate(v interface{}) error {
+	return (*Router)(nil).Validate(v)
+}
+
+func (c *Context) Validate(v interface{}) error {
+	return c.router.Validate(v)
+}
+
+func (r *Router) Validator(name string, validator ValidatorFunc) {
+	if r.validators == nil {
+		r.validators = map[string]ValidatorFunc{}
+	}
+	r.validators[name] = validator
+}
+
+// Validate checks v against its `validate` tags. Rules registered on this router
+// or any router it is mounted under take precedence over the built-in ones.
+func (r *Router) Validate(v interface{}) error {
+	errs := ValidationErrors{}
+	r.validateValue(reflect.ValueOf(v), "", &errs)
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (v ValidationErrors) Error() string {
+	reasons := make([]string, len(v))
+	for i, n := range v {
+		reasons[i] = n.Field + " " + n.Reason
+	}
+	return strings.Join(reasons, "; ")
+}
+
+func (r *Router) validator(name string) (ValidatorFunc, bool) {
+	for router := r; router != nil; router = router.parent {
+		if validator, ok := router.validators[name]; ok {
+			return validator, true
+		}
+	}
+	validator, ok := builtinValidators[name]
+	return validator, ok
+}
+
+func (r *Router) validateValue(value reflect.Value, path string, errs *ValidationErrors) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < value.NumField(); i++ {
+			field := valueType.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			fieldPath := path
+			if !field.Anonymous && path != "" {
+				fieldPath = path + "." + fieldName(field)
+			} else if !field.Anonymous {
+				fieldPath = fieldName(field)
+			}
+
+			if r.validateField(value.Field(i), field.Tag.Get(validate_tag), fieldPath, errs) {
+				r.validateValue(value.Field(i), fieldPath, errs)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			r.validateValue(value.Index(i), path + "[" + strconv.Itoa(i) + "]", errs)
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			r.validateValue(iter.Value(), path + "[" + fmt.Sprint(iter.Key().Interface()) + "]", errs)
+		}
+	}
+}
+
+// validateField applies the rules of a single tag and reports whether the field
+// passed, nested values are only checked when it did.
+func (r *Router) validateField(field reflect.Value, tag string, path string, errs *ValidationErrors) bool {
+	if tag == "" || tag == "-" {
+		return true
+	}
+
+	for _, rule := range splitRules(tag) {
+		name, param := rule, ""
+		if i := strings.Index(rule, validate_param_separator); i >= 0 {
+			name, param = rule[:i], rule[i + 1:]
+		}
+
+		// Only absent values skip the rules, a zero int still has to pass min=1.
+		if name != "required" && isNil(field) {
+			continue
+		}
+
+		validator, ok := r.validator(name)
+		if !ok {
+			RouterLogger.Warn("Unknown validation rule " + name + " on field " + path)
+			continue
+		}
+
+		if err := validator(field, param); err != nil {
+			*errs = append(*errs, FieldError{
+				Field: path,
+				Reason: err.Error(),
+			})
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitRules splits a tag into its rules. A regex pattern may contain commas,
+// so it takes the rest of the tag and has to be the last rule.
+func splitRules(tag string) []string {
+	rules := []string{}
+	for tag != "" {
+		if strings.HasPrefix(tag, "regex" + validate_param_separator) {
+			return append(rules, tag)
+		}
+
+		rule, rest, _ := strings.Cut(tag, validate_rule_separator)
+		rules = append(rules, rule)
+		tag = rest
+	}
+	return rules
+}
+
+func fieldName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
+
+func isEmpty(field reflect.Value) bool {
+	return !field.IsValid() || field.IsZero()
+}
+
+func isNil(field reflect.Value) bool {
+	if !field.IsValid() {
+		return true
+	}
+	return (field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface) && field.IsNil()
+}
+
+func indirect(field reflect.Value) reflect.Value {
+	for field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return field
+		}
+		field = field.Elem()
+	}
+	return field
+}
+
+func validateRequired(field reflect.Value, _ string) error {
+	field = indirect(field)
+	if isEmpty(field) {
+		return errors.New("is required")
+	}
+	if (field.Kind() == reflect.Slice || field.Kind() == reflect.Map) && field.Len() == 0 {
+		return errors.New("is required")
+	}
+	return nil
+}
+
+func validateMin(field reflect.Value, param string) error {
+	return compare(field, param, func(value, limit float64) bool { return value >= limit }, "at least")
+}
+
+func validateMax(field reflect.Value, param string) error {
+	return compare(field, param, func(value, limit float64) bool { return value <= limit }, "at most")
+}
+
+func validateLen(field reflect.Value, param string) error {
+	return compare(field, param, func(value, limit float64) bool { return value == limit }, "exactly")
+}
+
+func compare(field reflect.Value, param string, ok func(value, limit float64) bool, bound string) error {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return errors.New("has an invalid rule parameter " + strconv.Quote(param))
+	}
+
+	field = indirect(field)
+	value, unit := 0.0, ""
+
+	switch field.Kind() {
+	case reflect.String:
+		value, unit = float64(utf8.RuneCountInString(field.String())), " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		value, unit = float64(field.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = float64(field.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = float64(field.Uint())
+	case reflect.Float32, reflect.Float64:
+		value = field.Float()
+	default:
+		return errors.New("cannot be compared")
+	}
+
+	if !ok(value, limit) {
+		if unit == " items" {
+			return fmt.Errorf("must contain %s %s items", bound, param)
+		}
+		return fmt.Errorf("must be %s %s%s", bound, param, unit)
+	}
+	return nil
+}
+
+func validateRegex(field reflect.Value, param string) error {
+	pattern, ok := regexCache.Load(param)
+	if !ok {
+		compiled, err := regexp.Compile(param)
+		if err != nil {
+			return errors.New("has an invalid pattern " + strconv.Quote(param))
+		}
+		pattern, _ = regexCache.LoadOrStore(param, compiled)
+	}
+
+	if !pattern.(*regexp.Regexp).MatchString(fmt.Sprint(indirect(field).Interface())) {
+		return errors.New("must match " + param)
+	}
+	return nil
+}
+
+func validateEnum(field reflect.Value, param string) error {
+	options := strings.Split(param, validate_enum_separator)
+	value := fmt.Sprint(indirect(field).Interface())
+
+	for _, option := range options {
+		if value == option {
+			return nil
+		}
+	}
+	return errors.New("must be one of " + strings.Join(options, ", "))
+}
+
+func validateEmail(field reflect.Value, _ string) error {
+	value := fmt.Sprint(indirect(field).Interface())
+
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Address != value {
+		return errors.New("must be a valid email address")
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package core
+
+import (
+	"github.com/gorilla/websocket"
+	"net/http"
+	"time"
+)
+
+func (r *Router) WS(path string, resolver WebSocketResolver, config ...WebSocketConfig) *Route {
+	wsConfig := DefaultWebSocketConfig
+	if len(config) > 0 {
+		wsConfig = config[0]
+	}
+
+	return r.track(r.mux.HandleFunc(path, r.webSocketResolver(resolver, wsConfig)).Methods(http.MethodGet), resolver)
+}
+
+func IsCloseError(err error, codes ...int) bool {
+	return websocket.IsCloseError(err, codes...)
+}
+
+func (r *Router) webSocketResolver(resolver WebSocketResolver, config WebSocketConfig) func(res http.ResponseWriter, req *http.Request) {
+	upgrader := &websocket.Upgrader{
+		ReadBufferSize: config.ReadBufferSize,
+		WriteBufferSize: config.WriteBufferSize,
+		Subprotocols: config.Subprotocols,
+		CheckOrigin: config.CheckOrigin,
+	}
+
+	return func(res http.ResponseWriter, req *http.Request) {
+		profiler := TrackTime()
+
+		conn, err := upgrader.Upgrade(res, req, nil)
+		if err != nil {
+			RouterLogger.Warn("WebSocket upgrade failed:", err)
+			return
+		}
+
+		ctx := newWebSocketContext(r.getContext(res, req, profiler), conn, config)
+		sockets := r.root().webSockets
+		if !sockets.add(ctx) {
+			ctx.Close(websocket.CloseGoingAway, hub_shutdown_reason)
+			return
+		}
+		defer sockets.remove(ctx)
+
+		go ctx.keepAlive()
+
+		resolver(ctx)
+
+		ctx.Close(websocket.CloseNormalClosure, "")
+	}
+}
+
+func newWebSocketContext(ctx *Context, conn *websocket.Conn, config WebSocketConfig) *WebSocketContext {
+	wsCtx := &WebSocketContext{
+		Context: ctx,
+		Conn: conn,
+		config: config,
+		done: make(chan struct{}),
+		closeCode: websocket.CloseNoStatusReceived,
+	}
+
+	if config.MaxMessageSize > 0 {
+		conn.SetReadLimit(config.MaxMessageSize)
+	}
+	if config.PongTimeout > 0 {
+		conn.SetReadDeadline(time.Now().Add(config.PongTimeout))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(config.PongTimeout))
+		})
+	}
+
+	conn.SetCloseHandler(func(code int, reason string) error {
+		wsCtx.closeOnce.Do(func() {
+			wsCtx.setCloseStatus(code, reason)
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, ""), wsCtx.writeDeadline())
+			close(wsCtx.done)
+		})
+		return nil
+	})
+
+	return wsCtx
+}
+
+func (w *WebSocketContext) ReadMessage() (messageType int, data []byte, err error) {
+	return w.Conn.ReadMessage()
+}
+
+func (w *WebSocketContext) ReadJSON(v interface{}) error {
+	return w.Conn.ReadJSON(v)
+}
+
+func (w *WebSocketContext) ReadText() (string, error) {
+	_, data, err := w.Conn.ReadMessage()
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (w *WebSocketContext) WriteMessage(messageType int, data []byte) error {
+	w.writeMutex.Lock()
+	defer w.writeMutex.Unlock()
+
+	w.Conn.SetWriteDeadline(w.writeDeadline())
+	return w.Conn.WriteMessage(messageType, data)
+}
+
+func (w *WebSocketContext) WriteJSON(v interface{}) error {
+	w.writeMutex.Lock()
+	defer w.writeMutex.Unlock()
+
+	w.Conn.SetWriteDeadline(w.writeDeadline())
+	return w.Conn.WriteJSON(v)
+}
+
+func (w *WebSocketContext) WriteText(text string) error {
+	return w.WriteMessage(websocket.TextMessage, []byte(text))
+}
+
+func (w *WebSocketContext) Close(code int, reason string) error {
+	err := websocket.ErrCloseSent
+	w.closeOnce.Do(func() {
+		w.setCloseStatus(code, reason)
+		err = w.Conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), w.writeDeadline())
+		close(w.done)
+	})
+	w.Conn.Close()
+	return err
+}
+
+func (w *WebSocketContext) CloseStatus() (code int, reason string) {
+	w.closeMutex.Lock()
+	defer w.closeMutex.Unlock()
+
+	return w.closeCode, w.closeReason
+}
+
+func (w *WebSocketContext) setCloseStatus(code int, reason string) {
+	w.closeMutex.Lock()
+	w.closeCode, w.closeReason = code, reason
+	w.closeMutex.Unlock()
+}
+
+func (w *WebSocketContext) Done() <-chan struct{} {
+	return w.done
+}
+
+func (w *WebSocketContext) keepAlive() {
+	if w.config.PingInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.config.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.Conn.WriteControl(websocket.PingMessage, nil, w.writeDeadline()); err != nil {
+				return
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *WebSocketContext) writeDeadline() time.Time {
+	if w.config.WriteTimeout <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(w.config.WriteTimeout)
+}
+
+func (s *webSocketSet) add(ctx *WebSocketContext) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.contexts[ctx] = true
+	return true
+}
+
+func (s *webSocketSet) remove(ctx *WebSocketContext) {
+	s.mutex.Lock()
+	delete(s.contexts, ctx)
+	s.mutex.Unlock()
+}
+
+// closeAll closes every open connection, connections opened afterwards are closed
+// right after the upgrade.
+func (s *webSocketSet) closeAll(code int, reason string) {
+	s.mutex.Lock()
+	s.closed = true
+	contexts := make([]*WebSocketContext, 0, len(s.contexts))
+	for ctx := range s.contexts {
+		contexts = append(contexts, ctx)
+	}
+	s.mutex.Unlock()
+
+	for _, ctx := range contexts {
+		ctx.Close(code, reason)
+	}
+}
@@ -1,10 +1,18 @@
 package core
 
 import (
-	"bufio"
+	"encoding/json"
+	"errors"
 	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc"
+	"io"
+	"net"
 	"net/http"
 	"os"
+	"reflect"
+	"sync"
+	"text/template"
 	"time"
 )
 
@@ -18,6 +26,14 @@ const (
 	tag_error = "ERR!"
 	tag_note = "NOTE"
 
+	syslog_facility_user = 1
+
+	log_date_format = "2006-01-02"
+	log_file_extension = ".log"
+	log_compressed_extension = ".gz"
+	log_cleanup_queue_size = 64
+	syslog_sockets = "/dev/log,/var/run/syslog,/var/run/log"
+
 	cors = "Access-Control-Allow-"
 	cors_origin = cors + "Origin"
 	cors_credentials = cors + "Credentials"
@@ -25,6 +41,81 @@ const (
 	cors_headers = cors + "Headers"
 
 	router_init_func_name = "Init"
+	router_field_name = "Router"
+
+	debug_routes_path = "/_winter/routes"
+
+	ws_ping_interval = 30 * time.Second
+	ws_pong_timeout = 60 * time.Second
+	ws_write_timeout = 10 * time.Second
+	ws_buffer_size = 1024
+
+	hub_send_buffer_size = 256
+	hub_shutdown_reason = "server shutdown"
+
+	PresenceConnect PresenceEventType = "connect"
+	PresenceDisconnect PresenceEventType = "disconnect"
+	PresenceJoin PresenceEventType = "join"
+	PresenceLeave PresenceEventType = "leave"
+
+	sse_content_type = "text/event-stream"
+	sse_last_event_id = "Last-Event-ID"
+
+	bind_tag_path = "path"
+	bind_tag_query = "query"
+	bind_tag_header = "header"
+
+	validate_tag = "validate"
+	validate_rule_separator = ","
+	validate_param_separator = "="
+	validate_enum_separator = "|"
+
+	header_request_id = "X-Request-ID"
+	header_accept = "Accept"
+	header_accept_language = "Accept-Language"
+	header_content_type = "Content-Type"
+	header_allow = "Allow"
+	header_method_override = "X-HTTP-Method-Override"
+
+	method_override_field = "_method"
+
+	mime_json = "application/json"
+	mime_xml = "application/xml"
+	mime_msgpack = "application/msgpack"
+	mime_cbor = "application/cbor"
+	mime_text = "text/plain; charset=utf-8"
+	mime_problem_json = "application/problem+json"
+	mime_form = "application/x-www-form-urlencoded"
+	mime_multipart_form = "multipart/form-data"
+	mime_grpc = "application/grpc"
+	mime_protobuf = "application/protobuf"
+
+	jsonrpc_version = "2.0"
+
+	JSONRPCParseError = -32700
+	JSONRPCInvalidRequest = -32600
+	JSONRPCMethodNotFound = -32601
+	JSONRPCInvalidParams = -32602
+	JSONRPCInternalError = -32603
+
+	twirp_malformed = "malformed"
+	twirp_bad_route = "bad_route"
+	twirp_internal = "internal"
+	twirp_unknown = "unknown"
+
+	problem_default_type = "about:blank"
+
+	// ListenFDEnv names the file descriptor of an inherited listening socket, set by winter run.
+	ListenFDEnv = "WINTER_LISTEN_FD"
+
+	AccessLogCombined = "combined"
+	AccessLogJSON = "json"
+
+	access_log_combined_template = `{{.RemoteIP}} - - [{{.Time.Format "02/Jan/2006:15:04:05 -0700"}}] ` +
+		`"{{.Method}} {{.URI}} {{.Proto}}" {{.Status}} {{.Bytes}} "{{.Referer}}" "{{.UserAgent}}"`
+
+	request_id_key contextKey = "request_id"
+	panic_router_key contextKey = "panic_router"
 
 	bad_os = "windows"
 
@@ -35,10 +126,94 @@ const (
 		"  \\/_/   \\/_/   \\/_/   \\/_/ \\/_/     \\/_/   \\/_____/   \\/_/ /_/ \n"
 )
 
+const (
+	LevelNote LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+const (
+	FormatText LogFormat = iota
+	FormatJSON
+)
+
 var (
 	MainLogger = NewLogger("main")
 	RequestLogger = NewLogger("request")
 	RouterLogger = NewLogger("router")
+
+	ErrStreamingUnsupported = errors.New("response writer does not support streaming")
+	ErrInvalidRouter = errors.New("invalid router")
+	ErrUnknownRoute = errors.New("unknown route")
+	ErrUnknownClient = errors.New("unknown WebSocket client")
+	ErrClientGone = errors.New("WebSocket client is closed or was dropped")
+
+	routerMethods = []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+		http.MethodConnect,
+		http.MethodOptions,
+		http.MethodTrace,
+	}
+
+	// DefaultCodecs are available on every router, the first one is used
+	// when the client does not ask for a specific type. XMLCodec is not among them,
+	// browsers list application/xml in their Accept header and would get XML.
+	DefaultCodecs = []Codec{
+		JSONCodec{},
+		MsgPackCodec{},
+		CBORCodec{},
+		TextCodec{},
+	}
+
+	// twirpCodes are the Twirp error codes sent for the statuses of ErrorMap errors.
+	twirpCodes = map[int]string{
+		http.StatusBadRequest: "invalid_argument",
+		http.StatusUnauthorized: "unauthenticated",
+		http.StatusForbidden: "permission_denied",
+		http.StatusNotFound: "not_found",
+		http.StatusRequestTimeout: "deadline_exceeded",
+		http.StatusConflict: "already_exists",
+		http.StatusPreconditionFailed: "failed_precondition",
+		http.StatusUnprocessableEntity: "invalid_argument",
+		http.StatusTooManyRequests: "resource_exhausted",
+		http.StatusInternalServerError: twirp_internal,
+		http.StatusNotImplemented: "unimplemented",
+		http.StatusServiceUnavailable: "unavailable",
+	}
+	twirpStatuses = map[string]int{
+		"canceled": http.StatusRequestTimeout,
+		"unknown": http.StatusInternalServerError,
+		"invalid_argument": http.StatusBadRequest,
+		"malformed": http.StatusBadRequest,
+		"deadline_exceeded": http.StatusRequestTimeout,
+		"not_found": http.StatusNotFound,
+		"bad_route": http.StatusNotFound,
+		"already_exists": http.StatusConflict,
+		"permission_denied": http.StatusForbidden,
+		"unauthenticated": http.StatusUnauthorized,
+		"resource_exhausted": http.StatusTooManyRequests,
+		"failed_precondition": http.StatusPreconditionFailed,
+		"aborted": http.StatusConflict,
+		"out_of_range": http.StatusBadRequest,
+		"unimplemented": http.StatusNotImplemented,
+		"internal": http.StatusInternalServerError,
+		"unavailable": http.StatusServiceUnavailable,
+		"dataloss": http.StatusInternalServerError,
+	}
+
+	DefaultWebSocketConfig = WebSocketConfig{
+		ReadBufferSize: ws_buffer_size,
+		WriteBufferSize: ws_buffer_size,
+		PingInterval: ws_ping_interval,
+		PongTimeout: ws_pong_timeout,
+		WriteTimeout: ws_write_timeout,
+	}
 )
 
 // server.go
@@ -50,6 +225,9 @@ type (
 		OnStart(onStart func(addr string))
 		OnError(onErr func(err error))
 		OnShutdown(onShutdown func(err error))
+		OnPanic(sample interface{}, resolver PanicResolver)
+
+		GRPC(register func(g *grpc.Server), opts ...grpc.ServerOption)
 	}
 	Server struct {
 		*Router
@@ -58,20 +236,41 @@ type (
 
 		Debug bool
 		GracefulShutdown bool
+		// Recovery answers panics in resolvers and middleware with the 500 error
+		// instead of dropping the connection, enabled by default.
+		Recovery bool
+		// MethodOverride lets POST requests name their method in the X-HTTP-Method-Override
+		// header or the _method form field, for HTML forms that can only POST.
+		MethodOverride bool
+		HTTP2 HTTP2Config
 
 		Headers ServerHeaders
 		CORS ServerCORSHeaders
 
+		Hub *Hub
+
 		NativeServer *http.Server
 
 		onStart func(addr string)
 		onError func(err error)
 		onShutdown func(err error)
+
+		panicResolvers map[reflect.Type]PanicResolver
+		grpcServer *grpc.Server
 	}
 
 	ServerConfig struct {
 	}
 
+	HTTP2Config struct {
+		// H2C serves HTTP/2 without TLS, for servers behind a proxy that speaks it.
+		H2C bool
+		// MaxConcurrentStreams and MaxReadFrameSize use the x/net/http2 defaults when zero.
+		MaxConcurrentStreams uint32
+		MaxReadFrameSize uint32
+		IdleTimeout time.Duration
+	}
+
 	ServerHeaders struct {
 		headerMap map[string]string
 	}
@@ -86,21 +285,79 @@ type (
 	IRouter interface {
 		GetHandler() *mux.Router
 
-		Set(path string, router interface{})
-		SetHandler(path string, handler http.Handler)
-
-		All(path string, resolver Resolver)
-		Get(path string, resolver Resolver)
-		Put(path string, resolver Resolver)
-		Post(path string, resolver Resolver)
-		Delete(path string, resolver Resolver)
-		Handle(path string, resolver Resolver, methods ...string)
+		Set(path string, router interface{}) error
+		Group(prefix string, init func(g *Router), config ...GroupConfig) *Router
+		SetHandler(path string, handler http.Handler) *Route
+
+		All(path string, resolver Resolver) *Route
+		Get(path string, resolver Resolver) *Route
+		Put(path string, resolver Resolver) *Route
+		Post(path string, resolver Resolver) *Route
+		Delete(path string, resolver Resolver) *Route
+		Patch(path string, resolver Resolver) *Route
+		Head(path string, resolver Resolver) *Route
+		Options(path string, resolver Resolver) *Route
+		Connect(path string, resolver Resolver) *Route
+		Trace(path string, resolver Resolver) *Route
+		Handle(path string, resolver Resolver, methods ...string) *Route
+		URL(name string, params ...string) (string, error)
+		WS(path string, resolver WebSocketResolver, config ...WebSocketConfig) *Route
+		Routes() []RouteInfo
 
 		Use(resolver MiddlewareResolver)
+		Validator(name string, validator ValidatorFunc)
+		Codec(codec Codec)
+		MapError(target error, code int)
+		JSONRPC(path string, registry *JSONRPCRegistry) *Route
+		Messages(language string, messages map[int]string)
+		NotFound(resolver Resolver)
+		MethodNotAllowed(resolver Resolver)
 	}
 	Router struct {
 		mux *mux.Router
 		Errors *ErrorMap
+
+		// ProblemDetails sends errors returned from Result resolvers as RFC 7807
+		// application/problem+json, routers mounted under this one inherit it.
+		ProblemDetails bool
+
+		parent *Router
+		namePrefix string
+		// children and matcher let the root router find the mounted router
+		// that owns an unmatched request.
+		children []*Router
+		matcher *mux.Route
+		notFound Resolver
+		methodNotAllowed Resolver
+		middleware []string
+		// routes are the routes registered anywhere below the root router, kept on the root.
+		routes []*Route
+		// webSockets are the open WebSocket connections of every router, kept on the root.
+		webSockets *webSocketSet
+		validators map[string]ValidatorFunc
+		codecs []Codec
+		errorCodes []errorCode
+		catalogs map[string]map[int]*template.Template
+	}
+
+	// InitRouter is a router that is configured by Init once it is Set on another router.
+	InitRouter struct {
+		*Router
+		Init func(r *Router)
+	}
+
+	// Route is a registered route, Name makes it reachable through URL.
+	Route struct {
+		route *mux.Route
+		router *Router
+		handler string
+	}
+
+	GroupConfig struct {
+		// Host is a host template like "{tenant}.example.com" the group is limited to.
+		Host string
+		// Name prefixes the names of the group's routes.
+		Name string
 	}
 )
 
@@ -114,15 +371,38 @@ type (
 		GetParams() map[string]string
 		GetParam(key string) (string, bool)
 		GetBody(body interface{}) error
+		Bind(v interface{}) error
+		Validate(v interface{}) error
 
 		SendError(err Error)
 		SendSuccess(message interface{})
 		SendResponse(status int, message interface{})
+		Render(status int, v interface{})
+
+		SSE() (*EventStream, error)
+		Fail(err error) Response
+		ErrorResponse(code int, params ...Params) Response
+		SendErrorCode(code int, params ...Params)
+		Languages() []string
+
+		RequestID() string
+		Logger() *Logger
+
+		StatusCode() int
+		BytesWritten() int64
+		HeadersSent() bool
+		FirstWrite() time.Time
+
+		URL(name string, params ...string) (string, error)
+		Push(path string) error
 	}
 	Context struct {
 		Response http.ResponseWriter
 		Request *http.Request
 		TrackTime func() time.Duration
+
+		router *Router
+		writer *responseWriter
 	}
 
 	IMiddlewareContext interface {
@@ -135,8 +415,8 @@ type (
 	}
 
 	Response struct {
-		Status 	int 		`json:"status,omitempty"`
-		Message interface{} `json:"message,omitempty"`
+		Status 	int 		`json:"status,omitempty" xml:"status,omitempty"`
+		Message interface{} `json:"message,omitempty" xml:"message,omitempty"`
 	}
 
 	Resolver func(ctx *Context) Response
@@ -144,6 +424,354 @@ type (
 	MiddlewareResolver func(ctx *MiddlewareContext)
 )
 
+// rotate.go
+type (
+	// RotatingFile writes into <Path>-<date>.log and rolls it over at midnight
+	// or once it grows past MaxSize. Old files are kept as <Path>-<date>.<n>.log.
+	RotatingFile struct {
+		Path string
+
+		Daily bool
+		MaxSize int64
+		Compress bool
+		MaxBackups int
+		MaxAge time.Duration
+
+		mutex sync.Mutex
+		file *os.File
+		size int64
+		day string
+
+		// backupNumber is the last number given to a backup of backupDay, it only grows
+		// so a lower number is always an older backup.
+		backupDay string
+		backupNumber int
+
+		// cleanups are compressed and pruned by a single worker in rotation order.
+		cleanups chan rotatedFile
+		pending sync.WaitGroup
+		workerOnce sync.Once
+		signalOnce sync.Once
+	}
+
+	rotatedFile struct {
+		backup string
+		active string
+	}
+
+	backupFile struct {
+		path string
+		day string
+		// number is 0 for the last file of a day, which is the newest of that day.
+		number int
+		modTime time.Time
+	}
+)
+
+// accesslog.go
+type (
+	AccessLogEntry struct {
+		Time time.Time `json:"time"`
+		RequestID string `json:"request_id,omitempty"`
+		RemoteIP string `json:"remote_ip"`
+		Method string `json:"method"`
+		URI string `json:"uri"`
+		Proto string `json:"proto"`
+		Status int `json:"status"`
+		Bytes int64 `json:"bytes"`
+		Referer string `json:"referer,omitempty"`
+		UserAgent string `json:"user_agent,omitempty"`
+		Duration time.Duration `json:"duration_ns"`
+	}
+
+	contextKey string
+)
+
+// response_writer.go
+type (
+	// responseWriter is the writer behind every Context.Response. It records what
+	// was written so middleware can inspect it after Next.
+	responseWriter struct {
+		http.ResponseWriter
+
+		status int
+		bytes int64
+		firstWrite time.Time
+	}
+)
+
+// methods.go
+type (
+	// headResponseWriter drops the body of GET routes answering HEAD requests.
+	headResponseWriter struct {
+		http.ResponseWriter
+	}
+)
+
+// twirp.go
+type (
+	// TwirpError is the error body of Twirp responses, returned by CallTwirp
+	// and sent unchanged when a service method returns it.
+	TwirpError struct {
+		Code string `json:"code"`
+		Msg string `json:"msg"`
+		Meta map[string]string `json:"meta,omitempty"`
+	}
+)
+
+// jsonrpc.go
+type (
+	// JSONRPCRegistry holds the methods served by Router.JSONRPC and ServeWS,
+	// register them with RegisterRPC.
+	JSONRPCRegistry struct {
+		methods map[string]jsonRPCMethod
+	}
+
+	// JSONRPCError is the error object of JSON-RPC responses. Methods can return it
+	// to send a code of their own.
+	JSONRPCError struct {
+		Code int `json:"code"`
+		Message string `json:"message"`
+		Data interface{} `json:"data,omitempty"`
+	}
+
+	jsonRPCMethod func(ctx *Context, params json.RawMessage) (interface{}, error)
+
+	jsonRPCRequest struct {
+		JSONRPC string `json:"jsonrpc"`
+		Method string `json:"method"`
+		Params json.RawMessage `json:"params"`
+		ID json.RawMessage `json:"id"`
+	}
+
+	jsonRPCResponse struct {
+		JSONRPC string `json:"jsonrpc"`
+		Result json.RawMessage `json:"result,omitempty"`
+		Error *JSONRPCError `json:"error,omitempty"`
+		ID json.RawMessage `json:"id"`
+	}
+)
+
+// routes.go
+type (
+	RouteInfo struct {
+		Methods []string `json:"methods"`
+		Path string `json:"path"`
+		Host string `json:"host,omitempty"`
+		Name string `json:"name,omitempty"`
+		Handler string `json:"handler"`
+		// Middleware runs in this order before the handler, outermost router first.
+		Middleware []string `json:"middleware"`
+	}
+
+	walkedRoute struct {
+		route *mux.Route
+		tracked *Route
+		ancestors []*mux.Route
+	}
+)
+
+// recovery.go
+type (
+	PanicResolver func(ctx *Context, recovered interface{}) Response
+)
+
+// result.go
+type (
+	ResultResolver func(ctx *Context) (interface{}, error)
+
+	Problem struct {
+		Type string `json:"type"`
+		Title string `json:"title"`
+		Status int `json:"status"`
+		Detail interface{} `json:"detail,omitempty"`
+		Instance string `json:"instance,omitempty"`
+	}
+
+	errorCode struct {
+		target error
+		code int
+	}
+)
+
+// i18n.go
+type (
+	// Params fill in the placeholders of a localized message, like {{.field}}.
+	Params map[string]interface{}
+)
+
+// websocket.go
+type (
+	IWebSocketContext interface {
+		ReadMessage() (messageType int, data []byte, err error)
+		ReadJSON(v interface{}) error
+		ReadText() (string, error)
+
+		WriteMessage(messageType int, data []byte) error
+		WriteJSON(v interface{}) error
+		WriteText(text string) error
+
+		Close(code int, reason string) error
+		CloseStatus() (code int, reason string)
+		Done() <-chan struct{}
+	}
+	WebSocketContext struct {
+		*Context
+		Conn *websocket.Conn
+
+		config WebSocketConfig
+		writeMutex sync.Mutex
+
+		done chan struct{}
+		closeOnce sync.Once
+		// closeMutex guards the close status, set by Close or by the reading goroutine
+		// when the peer closes and read from any goroutine.
+		closeMutex sync.Mutex
+		closeCode int
+		closeReason string
+	}
+
+	WebSocketConfig struct {
+		ReadBufferSize int
+		WriteBufferSize int
+		Subprotocols []string
+		CheckOrigin func(req *http.Request) bool
+
+		// PingInterval is how often a ping frame is sent to the peer,
+		// PongTimeout is how long a connection may stay silent before reads fail.
+		PingInterval time.Duration
+		PongTimeout time.Duration
+		WriteTimeout time.Duration
+		MaxMessageSize int64
+	}
+
+	WebSocketResolver func(ctx *WebSocketContext)
+
+	webSocketSet struct {
+		mutex sync.Mutex
+		contexts map[*WebSocketContext]bool
+		closed bool
+	}
+)
+
+// hub.go
+type (
+	IHub interface {
+		Handler(resolver HubResolver) WebSocketResolver
+		OnPresence(onPresence func(event PresenceEvent))
+
+		Client(id string) (*HubClient, bool)
+		Members(room string) []string
+
+		Send(id string, messageType int, data []byte) bool
+		SendJSON(id string, v interface{}) error
+		Broadcast(room string, messageType int, data []byte)
+		BroadcastJSON(room string, v interface{}) error
+
+		Shutdown(code int, reason string)
+	}
+	Hub struct {
+		// SendBufferSize is the number of outgoing messages queued per connection.
+		// A connection whose queue is full is dropped instead of blocking the sender.
+		SendBufferSize int
+
+		mutex sync.RWMutex
+		clients map[string]*HubClient
+		rooms map[string]map[string]*HubClient
+		onPresence func(event PresenceEvent)
+		closed bool
+	}
+
+	HubClient struct {
+		*WebSocketContext
+		ID string
+
+		hub *Hub
+		send chan hubMessage
+		rooms map[string]bool
+	}
+
+	hubMessage struct {
+		messageType int
+		data []byte
+	}
+
+	PresenceEventType string
+	PresenceEvent struct {
+		Type PresenceEventType
+		Room string
+		Client *HubClient
+	}
+
+	HubResolver func(client *HubClient)
+)
+
+// sse.go
+type (
+	IEventStream interface {
+		Event(name string, data interface{}) error
+		Data(data interface{}) error
+		ID(id string)
+		Retry(retry time.Duration) error
+		Comment(text string) error
+		Heartbeat(interval time.Duration)
+
+		LastEventID() string
+		Done() <-chan struct{}
+	}
+	EventStream struct {
+		ctx *Context
+		controller *http.ResponseController
+
+		mutex sync.Mutex
+		id string
+		lastEventID string
+	}
+)
+
+// bind.go
+type (
+	ParamError struct {
+		Source string
+		Field string
+		Value string
+		Err error
+	}
+)
+
+// validate.go
+type (
+	// ValidatorFunc checks a single field against the rule's parameter,
+	// the returned error message is used as the failure reason.
+	ValidatorFunc func(field reflect.Value, param string) error
+
+	FieldError struct {
+		Field string `json:"field"`
+		Reason string `json:"reason"`
+	}
+	ValidationErrors []FieldError
+)
+
+// codec.go
+type (
+	Codec interface {
+		ContentType() string
+		Encode(w io.Writer, v interface{}) error
+		Decode(r io.Reader, v interface{}) error
+	}
+
+	JSONCodec struct{}
+	XMLCodec struct{}
+	MsgPackCodec struct{}
+	CBORCodec struct{}
+	TextCodec struct{}
+
+	mediaRange struct {
+		mediaType string
+		quality float64
+	}
+)
+
 // error.go
 type (
 	IError interface {
@@ -153,17 +781,27 @@ type (
 	}
 	Error struct {
 		*Response
+
+		// code is the ErrorMap code the error was registered under,
+		// it lets an error returned from a Result resolver be looked up again.
+		code int
 	}
 	BindError struct {
 		Code int
-		*Error
+		Err *Error
 	}
 
 	IErrorMap interface {
 		Get(code int) *Error
 		Set(code int, err *Error)
+		Has(code int) bool
+	}
+	// ErrorMap holds a router's own errors. Lookups fall back to the map of the
+	// router it is mounted under and finally to HTTPErrors.
+	ErrorMap struct {
+		errors map[int]*Error
+		parent *ErrorMap
 	}
-	ErrorMap map[int]*Error
 )
 
 // logger.go
@@ -179,15 +817,49 @@ type (
 		Warnf(format string, mess ...interface{})
 		Note(mess ...interface{})
 		Notef(format string, mess ...interface{})
+
+		With(key string, value interface{}) *Logger
+		SetOutput(w io.Writer) *Logger
+		LogIntoFile(filePath string) *Logger
+		LogIntoRotatingFile(file *RotatingFile) *Logger
+		Close() error
 	}
 	Logger struct {
 		Name string
-		logIntoFile bool
-		logFile *os.File
 
-		fileWriter *bufio.Writer
-		writer func(a ...interface{}) (n int, err error)
-		writerf func(format string, a ...interface{}) (n int, err error)
-		writerln func(a ...interface{}) (n int, err error)
+		// Level is the lowest level that is written, untagged Log lines are always written.
+		Level LogLevel
+		Format LogFormat
+
+		fields []logField
+		sink *logSink
+		logFile *RotatingFile
+	}
+
+	LogLevel int
+	LogFormat int
+
+	// LevelWriter is implemented by sinks that need the level of every line,
+	// like SyslogWriter mapping it to a severity.
+	LevelWriter interface {
+		WriteLevel(level LogLevel, p []byte) (n int, err error)
+	}
+
+	SyslogWriter struct {
+		Tag string
+
+		mutex sync.Mutex
+		conn net.Conn
+	}
+
+	logField struct {
+		key string
+		value interface{}
+	}
+
+	logSink struct {
+		mutex sync.Mutex
+		writer io.Writer
+		ansi bool
 	}
 )